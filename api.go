@@ -7,9 +7,12 @@ package p5
 import (
 	"image"
 	"image/color"
+	"io/fs"
 	"log"
 
 	"gioui.org/font"
+	"gioui.org/x/stroke"
+	"gonum.org/v1/gonum/spatial/r2"
 )
 
 // Push saves the current drawing style settings and transformations.
@@ -22,6 +25,25 @@ func Pop() {
 	gproc.Pop()
 }
 
+// BeginClip starts recording a clipping mask.
+// Shapes drawn until EndClip restrict all subsequent drawing to the area
+// they enclose, until the current context is restored with Pop.
+func BeginClip() {
+	gproc.BeginClip()
+}
+
+// BeginInvertedClip starts recording an inverted clipping mask.
+// Shapes drawn until EndClip restrict all subsequent drawing to the area
+// outside of them, until the current context is restored with Pop.
+func BeginInvertedClip() {
+	gproc.BeginInvertedClip()
+}
+
+// EndClip stops recording the clipping mask and applies it.
+func EndClip() {
+	gproc.EndClip()
+}
+
 // Canvas defines the dimensions of the painting area, in pixels.
 func Canvas(w, h int) {
 	gproc.Canvas(w, h)
@@ -49,16 +71,39 @@ func StrokeWidth(v float64) {
 	gproc.StrokeWidth(v)
 }
 
+// StrokeCap sets the style of the ends of strokes.
+//
+// The default style is stroke.RoundCap.
+func StrokeCap(cap stroke.StrokeCap) {
+	gproc.StrokeCap(cap)
+}
+
 // Fill sets the color used to fill shapes.
 func Fill(c color.Color) {
 	gproc.Fill(c)
 }
 
-// LoadFonts sets the fonts collection to use for text.
+// LoadFonts sets the fonts collection to use for text, replacing the
+// default Go fonts.
+// Fonts of the collection are selected with TextFont.
 func LoadFonts(fnt []font.FontFace) {
 	gproc.LoadFonts(fnt)
 }
 
+// LoadFont reads the TTF, OTF or TTC font file at the provided path and
+// adds its faces to the fonts collection.
+// It returns the font of the first face, to be selected with TextFont.
+func LoadFont(fname string) (font.Font, error) {
+	return gproc.LoadFont(fname)
+}
+
+// LoadFontFS reads the TTF, OTF or TTC font file at the provided path of
+// the fsys file system and adds its faces to the fonts collection.
+// It returns the font of the first face, to be selected with TextFont.
+func LoadFontFS(fsys fs.FS, fname string) (font.Font, error) {
+	return gproc.LoadFontFS(fsys, fname)
+}
+
 // TextSize sets the text size.
 func TextSize(size float64) {
 	gproc.TextSize(size)
@@ -69,7 +114,118 @@ func TextFont(fnt font.Font) {
 	gproc.TextFont(fnt)
 }
 
+// TextStyle sets the weight and slant of the current text font.
+// The typeface of the font is kept, while TextFont replaces it all.
+func TextStyle(style FontStyle) {
+	gproc.TextStyle(style)
+}
+
+// TextColor sets the color used to fill text.
+// A nil color disables filling text.
+//
+// The default color is black.
+func TextColor(c color.Color) {
+	gproc.TextColor(c)
+}
+
+// TextStroke sets the color used to outline text, with the current
+// stroke width.
+// A nil color disables outlining text.
+//
+// By default, text is not outlined.
+func TextStroke(c color.Color) {
+	gproc.TextStroke(c)
+}
+
+// TextAlign sets how text is positioned relative to the point it is
+// drawn at: horizontally with AlignLeft, AlignCenter or AlignRight, and
+// vertically with AlignTop, AlignCenter, AlignBaseline or AlignBottom.
+//
+// The default alignment is AlignLeft and AlignBaseline.
+func TextAlign(horizontal, vertical TextAlignment) {
+	gproc.TextAlign(horizontal, vertical)
+}
+
+// TextBox draws txt within the box described by the (x,y,w,h) parameters,
+// interpreted according to the current rectangle mode.
+//
+// Lines are broken at explicit line breaks and wrapped at word boundaries
+// to fit the width of the box. Lines overflowing its height are dropped
+// and the last drawn line ends with an ellipsis.
+// Text is positioned in the box according to the current text alignment,
+// where AlignBaseline behaves as AlignTop.
+func TextBox(txt string, x, y, w, h float64) {
+	gproc.TextBox(txt, x, y, w, h)
+}
+
+// TextWidth returns the width of txt, in user coordinates, when drawn
+// with the current text style.
+// The width of multi-line text is the width of its widest line.
+func TextWidth(txt string) float64 {
+	return gproc.TextWidth(txt)
+}
+
+// TextAscent returns the ascent of the current font at the current text
+// size, in user coordinates: the distance from the baseline to the top
+// of a line of text.
+func TextAscent() float64 {
+	return gproc.TextAscent()
+}
+
+// TextDescent returns the descent of the current font at the current text
+// size, in user coordinates: the distance from the baseline to the bottom
+// of a line of text.
+func TextDescent() float64 {
+	return gproc.TextDescent()
+}
+
+// TextBounds returns the bounding box, in user coordinates, of txt when
+// drawn at (x,y) with Text and the current text style and alignment.
+// The current transformation is not taken into account.
+func TextBounds(txt string, x, y float64) r2.Box {
+	return gproc.TextBounds(txt, x, y)
+}
+
+// TextToPath returns the outlines of the glyphs of txt, as drawn at (x,y)
+// by Text with the current text style and alignment.
+//
+// The returned path is retained, as if created with NewPath.
+// Glyphs without outlines, such as bitmap emojis, and glyphs from system
+// fonts outside of the fonts collection are left out.
+func TextToPath(txt string, x, y float64) *Path {
+	return gproc.TextToPath(txt, x, y)
+}
+
+// TextToPoints returns points evenly spaced along the outlines of the
+// glyphs of txt, as drawn at (x,y) by Text, in user coordinates.
+//
+// The sampleFactor sets the number of points per unit of outline length.
+func TextToPoints(txt string, x, y, sampleFactor float64) (xs, ys []float64) {
+	return gproc.TextToPoints(txt, x, y, sampleFactor)
+}
+
+// TextOnPath draws txt along the path, with the baseline of each glyph
+// following the path and each glyph rotated to the direction of the path
+// at its middle.
+//
+// The offset is the distance along the path, in user coordinates, where
+// the text is anchored according to the current horizontal alignment.
+// The current vertical alignment places the text across the path.
+// Line breaks are drawn as spaces, and glyphs whose middle lies beyond
+// the ends of the path are left out.
+func TextOnPath(txt string, path *Path, offset float64) {
+	gproc.TextOnPath(txt, path, offset)
+}
+
+// TextLeading sets the spacing between the baselines of lines of text,
+// in the same unit as TextSize.
+// A zero leading selects a spacing proportional to the text size.
+func TextLeading(leading float64) {
+	gproc.TextLeading(leading)
+}
+
 // Text draws txt on the screen at (x,y).
+// Lines of text are positioned according to the current text alignment.
 func Text(txt string, x, y float64) {
 	gproc.Text(txt, x, y)
 }
@@ -153,7 +309,89 @@ func ReadImage(fname string) (image.Image, error) {
 	return gproc.ReadImage(fname)
 }
 
+// ImageMode sets how the position of images drawn with DrawImage is
+// interpreted.
+// With ModeCenter or ModeRadius, (x,y) is the center of the image.
+// Otherwise, (x,y) is its upper-left corner.
+//
+// The default mode is ModeCorner.
+func ImageMode(mode ShapeMode) {
+	gproc.ImageMode(mode)
+}
+
 // DrawImage draws the provided image at (x,y).
 func DrawImage(img image.Image, x, y float64) {
 	gproc.DrawImage(img, x, y)
 }
+
+// LoadPixels captures the content of the canvas drawn so far in the
+// current frame and returns it.
+//
+// The returned image holds the pixels read by Get and modified by Set,
+// until the next call to LoadPixels. Changes to the image are drawn on
+// the canvas with UpdatePixels.
+func LoadPixels() (*image.RGBA, error) {
+	return gproc.LoadPixels()
+}
+
+// UpdatePixels draws the pixels loaded with LoadPixels, and modified
+// since, over the canvas.
+// Pixels are drawn as is, regardless of the current transformation.
+func UpdatePixels() {
+	gproc.UpdatePixels()
+}
+
+// Get returns the color of the pixel at (x,y), in pixels from the
+// upper-left corner of the canvas.
+//
+// Get reads the pixels loaded by the last call to LoadPixels, and loads
+// them first if needed. Pixels outside of the canvas are transparent.
+func Get(x, y int) color.Color {
+	return gproc.Get(x, y)
+}
+
+// Set sets the color of the pixel at (x,y), in pixels from the upper-left
+// corner of the canvas.
+//
+// Set modifies the pixels loaded by the last call to LoadPixels, and loads
+// them first if needed. The canvas shows the change after UpdatePixels.
+// Pixels outside of the canvas are ignored.
+func Set(x, y int, c color.Color) {
+	gproc.Set(x, y, c)
+}
+
+// Shade draws over the canvas the colors returned by f for every pixel of
+// the canvas, evaluated at the center of the pixel in user coordinates.
+// A nil color leaves the pixel unchanged.
+//
+// Pixels are drawn as is, regardless of the current transformation.
+// Rows of pixels are evaluated concurrently, across all CPU cores: f
+// must be safe for concurrent use.
+func Shade(f func(x, y float64) color.Color) {
+	gproc.Shade(f)
+}
+
+// CreateGraphics returns a new transparent Graphics of w×h pixels, with
+// the default drawing style and the current fonts collection.
+func CreateGraphics(w, h int) (*Graphics, error) {
+	return gproc.CreateGraphics(w, h)
+}
+
+// ReadSVG reads the SVG document at the provided path.
+func ReadSVG(fname string) (*SVG, error) {
+	return gproc.ReadSVG(fname)
+}
+
+// DrawSVG draws the SVG document in the w×h rectangle at (x,y).
+//
+// As with DrawImage, (x,y) is the center of the rectangle with ModeCenter
+// or ModeRadius image modes, and its corner otherwise.
+// The document is drawn upright and its viewBox is stretched to fill the
+// rectangle. When w or h is not positive, the intrinsic size of the
+// document is used instead.
+//
+// Shapes are drawn with their own fill and stroke styles, under the
+// current transformation.
+func DrawSVG(doc *SVG, x, y, w, h float64) {
+	gproc.DrawSVG(doc, x, y, w, h)
+}
@@ -4,6 +4,21 @@
 
 package p5
 
+// RectMode sets how the parameters of Rect and Square are interpreted.
+//
+// The default mode is ModeCorner.
+func RectMode(mode ShapeMode) {
+	gproc.RectMode(mode)
+}
+
+// EllipseMode sets how the parameters of Ellipse, Circle and Arc are
+// interpreted.
+//
+// The default mode is ModeCenter.
+func EllipseMode(mode ShapeMode) {
+	gproc.EllipseMode(mode)
+}
+
 // Ellipse draws an ellipse at (x,y) with the provided width and height.
 func Ellipse(x, y, w, h float64) {
 	gproc.Ellipse(x, y, w, h)
@@ -17,8 +32,23 @@ func Circle(x, y, d float64) {
 // Arc draws an ellipsoidal arc centered at (x,y), with the provided
 // width and height, and a path from the beg to end radians.
 // Positive angles denote a counter-clockwise path.
-func Arc(x, y, w, h float64, beg, end float64) {
-	gproc.Arc(x, y, w, h, beg, end)
+// The mode selects how the arc is closed.
+func Arc(x, y, w, h float64, beg, end float64, mode ArcMode) {
+	gproc.Arc(x, y, w, h, beg, end, mode)
+}
+
+// Point draws a point at (x,y).
+//
+// Points are drawn with the stroke color. Their size is the stroke width,
+// and their shape follows the stroke cap: points are disks with round
+// caps, and squares otherwise.
+func Point(x, y float64) {
+	gproc.Point(x, y)
+}
+
+// Points draws a point at each (xs[i],ys[i]) coordinates, as a single shape.
+func Points(xs, ys []float64) {
+	gproc.Points(xs, ys)
 }
 
 // Line draws a line between (x1,y1) and (x2,y2).
@@ -33,13 +63,21 @@ func Quad(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
 }
 
 // Rect draws a rectangle at (x,y) with width w and height h.
-func Rect(x, y, w, h float64) {
-	gproc.Rect(x, y, w, h)
+//
+// The corners of the rectangle are rounded when radii are provided:
+// a single radius is applied to all corners, otherwise radii are, in
+// order, the radii of the top-left, top-right, bottom-right and
+// bottom-left corners. Missing radii take the value of the previous one.
+func Rect(x, y, w, h float64, radii ...float64) {
+	gproc.Rect(x, y, w, h, radii...)
 }
 
 // Square draws a square at (x,y) with size s.
-func Square(x, y, s float64) {
-	gproc.Square(x, y, s)
+//
+// The corners of the square are rounded when radii are provided,
+// as for Rect.
+func Square(x, y, s float64, radii ...float64) {
+	gproc.Square(x, y, s, radii...)
 }
 
 // Triangle draws a triangle, connecting the 3 points (x1,y1), (x2,y2)
@@ -61,6 +99,20 @@ func Curve(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
 	gproc.Curve(x1, y1, x2, y2, x3, y3, x4, y4)
 }
 
+// CurvePoint evaluates the Catmull-Rom curve going from b to c, with the
+// a and d control points, at t in [0,1].
+// CurvePoint honors the tension set with CurveTightness.
+func CurvePoint(a, b, c, d, t float64) float64 {
+	return gproc.CurvePoint(a, b, c, d, t)
+}
+
+// CurveTangent evaluates the derivative of the Catmull-Rom curve going
+// from b to c, with the a and d control points, at t in [0,1].
+// CurveTangent honors the tension set with CurveTightness.
+func CurveTangent(a, b, c, d, t float64) float64 {
+	return gproc.CurveTangent(a, b, c, d, t)
+}
+
 // CurveTightness determines how the curve fits to the Curve vertex points.
 // CurveTightness controls the Catmull-Rom tau tension.
 //
@@ -68,3 +120,29 @@ func Curve(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
 func CurveTightness(v float64) {
 	gproc.CurveTightness(v)
 }
+
+// NewPath creates a new path, made of connected lines and curves.
+//
+// Unlike shapes, the path is not drawn when it ends. It is retained to
+// be drawn any number of times, e.g. once per frame, with DrawPath.
+func NewPath() *Path {
+	return gproc.NewPath()
+}
+
+// DrawPath draws the path with the current style and transformation.
+//
+// The outline and stroke of the path are computed once and reused for
+// later calls, as long as neither the path nor the stroke style change.
+func DrawPath(path *Path) {
+	gproc.DrawPath(path)
+}
+
+// ParsePathData parses the SVG path data, such as "M 10 10 L 20 10 Z",
+// and returns the path it describes, in user coordinates.
+//
+// All commands are supported, in their absolute and relative forms:
+// M, L, H, V, C, S, Q, T, A and Z.
+// The returned path is retained, as if created with NewPath.
+func ParsePathData(data string) (*Path, error) {
+	return gproc.ParsePathData(data)
+}
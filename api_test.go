@@ -13,6 +13,7 @@ import (
 	"runtime"
 	"testing"
 
+	"gioui.org/x/stroke"
 	"golang.org/x/image/draw"
 )
 
@@ -120,7 +121,8 @@ func TestAPIShapes(t *testing.T) {
 
 			Stroke(color.Black)
 			StrokeWidth(5)
-			Arc(300, 100, 80, 20, 0, 1.5*math.Pi)
+			Fill(nil)
+			Arc(300, 100, 160, 40, 0, 1.5*math.Pi, ArcOpen)
 
 			Stroke(color.RGBA{R: 255, A: 128})
 			Line(300, 0, 300, 400)
@@ -404,3 +406,202 @@ func TestDrawImage(t *testing.T) {
 	)
 	proc.Run(t)
 }
+
+func TestShapeMode(t *testing.T) {
+	type box struct{ x, y, w, h float64 }
+	for _, tc := range []struct {
+		mode ShapeMode
+		args box
+		want box
+	}{
+		{ModeCorner, box{10, 20, 30, 40}, box{10, 20, 30, 40}},
+		{ModeCorners, box{10, 20, 30, 40}, box{10, 20, 20, 20}},
+		{ModeCorners, box{30, 40, 10, 20}, box{10, 20, 20, 20}},
+		{ModeCenter, box{10, 20, 30, 40}, box{-5, 0, 30, 40}},
+		{ModeRadius, box{10, 20, 30, 40}, box{-20, -20, 60, 80}},
+	} {
+		t.Run("", func(t *testing.T) {
+			var got box
+			got.x, got.y, got.w, got.h = tc.mode.box(tc.args.x, tc.args.y, tc.args.w, tc.args.h)
+			if got != tc.want {
+				t.Fatalf("invalid box: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+
+	proc := newProc(100, 100)
+	if got, want := proc.stk.cur().ellipseMode, ModeCenter; got != want {
+		t.Fatalf("invalid default ellipse mode: got=%v, want=%v", got, want)
+	}
+	proc.EllipseMode(ModeCorner)
+	x, y, w, h := proc.ellipse(10, 20, 30, 40)
+	if x != 25 || y != 40 || w != 15 || h != 20 {
+		t.Fatalf("invalid ellipse: got=(%v, %v, %v, %v)", x, y, w, h)
+	}
+}
+
+func TestRoundRect(t *testing.T) {
+	for _, tc := range []struct {
+		radii []float64
+		want  float64
+	}{
+		{nil, 80 * 60},
+		{[]float64{10}, 80*60 - (4-math.Pi)*10*10},
+		{[]float64{10, 0}, 80*60 - (4-math.Pi)*10*10/4},
+		{[]float64{0, 0, 20, 5}, 80*60 - (4-math.Pi)*(20*20+5*5)/4},
+		{[]float64{100}, 80*60 - (4-math.Pi)*30*30},
+	} {
+		t.Run("", func(t *testing.T) {
+			proc := newProc(200, 200)
+			proc.BeginClip()
+			proc.Rect(10, 10, 80, 60, tc.radii...)
+			got := float64(proc.stk.mask.shapes[0].area())
+			if math.Abs(got-tc.want) > 2 {
+				t.Fatalf("invalid area: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestArcModes(t *testing.T) {
+	const r = 40 // radius, in system coordinates.
+	for _, tc := range []struct {
+		mode ArcMode
+		want float64
+	}{
+		// half-disk: chord and pie areas coincide.
+		{ArcOpen, 0.5 * math.Pi * r * r},
+		{ArcChord, 0.5 * math.Pi * r * r},
+		{ArcPie, 0.5 * math.Pi * r * r},
+	} {
+		t.Run("", func(t *testing.T) {
+			proc := newProc(200, 200)
+			proc.PhysCanvas(200, 200, -10, 10, -20, 20)
+			proc.BeginClip()
+			proc.Arc(0, 0, 8, 16, 0, math.Pi, tc.mode)
+			got := math.Abs(float64(proc.stk.mask.shapes[0].area()))
+			if math.Abs(got-tc.want) > 2 {
+				t.Fatalf("invalid area: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+
+	proc := newProc(200, 200)
+	proc.BeginClip()
+	proc.Arc(100, 100, 80, 80, 0, 0.5*math.Pi, ArcPie)
+	proc.Arc(100, 100, 80, 80, 0, 0.5*math.Pi, ArcChord)
+	var (
+		pie   = math.Abs(float64(proc.stk.mask.shapes[0].area()))
+		chord = math.Abs(float64(proc.stk.mask.shapes[1].area()))
+	)
+	if got, want := pie-chord, 0.5*r*r; math.Abs(got-want) > 2 {
+		t.Fatalf("invalid pie-chord area: got=%v, want=%v", got, want)
+	}
+}
+
+func TestPoints(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	proc := newTestGProc(t, w, h,
+		func(*Proc) {
+			Background(color.Gray{Y: 220})
+		},
+		func(p *Proc) {
+			Stroke(color.RGBA{R: 255, A: 255})
+			StrokeWidth(20)
+			StrokeCap(stroke.RoundCap)
+			Point(50, 50)
+			Points([]float64{100, 150}, []float64{50, 50})
+
+			Stroke(color.RGBA{B: 255, A: 255})
+			StrokeCap(stroke.SquareCap)
+			Point(50, 100)
+			StrokeWidth(10)
+			Points([]float64{100, 150}, []float64{100, 100})
+
+			// points are not drawn without a stroke.
+			Stroke(nil)
+			Point(100, 150)
+
+			// nor do they contribute to a clipping mask.
+			Push()
+			Stroke(color.Black)
+			BeginClip()
+			Points([]float64{50, 150}, []float64{150, 150})
+			if got := len(p.stk.mask.shapes); got != 0 {
+				t.Errorf("points recorded in the clipping mask: got=%d shapes", got)
+			}
+			EndClip()
+			Pop()
+		},
+		"testdata/points.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestPointsMismatch(t *testing.T) {
+	defer func() {
+		e := recover()
+		if e == nil {
+			t.Fatalf("expected a panic")
+		}
+		if got, want := e.(error).Error(), "p5: length mismatch (xs=2, ys=1)"; got != want {
+			t.Fatalf("invalid panic message: got=%q, want=%q", got, want)
+		}
+	}()
+
+	proc := newProc(100, 100)
+	proc.Points([]float64{1, 2}, []float64{1})
+}
+
+func TestCurveEvaluation(t *testing.T) {
+	const tol = 1e-12
+	for _, tc := range []struct {
+		name string
+		f    func(a, b, c, d, t float64) float64
+		t    float64
+		want float64
+	}{
+		{"bezier-point-beg", BezierPoint, 0, 85},
+		{"bezier-point-mid", BezierPoint, 0.5, 50},
+		{"bezier-point-end", BezierPoint, 1, 15},
+		{"bezier-tangent-beg", BezierTangent, 0, 3 * (10 - 85)},
+		{"bezier-tangent-end", BezierTangent, 1, 3 * (15 - 90)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.f(85, 10, 90, 15, tc.t)
+			if math.Abs(got-tc.want) > tol {
+				t.Fatalf("invalid value: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+
+	proc := newProc(100, 100)
+	for _, tc := range []struct {
+		tau float64
+		t   float64
+		pt  float64
+		tan float64
+	}{
+		// evenly spaced control points yield a straight uniform curve.
+		{tau: 0, t: 0, pt: 1, tan: 1},
+		{tau: 0, t: 0.25, pt: 1.25, tan: 1},
+		{tau: 0, t: 1, pt: 2, tan: 1},
+		// a tight curve starts and ends with null tangents.
+		{tau: 1, t: 0, pt: 1, tan: 0},
+		{tau: 1, t: 0.5, pt: 1.5, tan: 1.5},
+		{tau: 1, t: 1, pt: 2, tan: 0},
+	} {
+		proc.CurveTightness(tc.tau)
+		if got := proc.CurvePoint(0, 1, 2, 3, tc.t); math.Abs(got-tc.pt) > tol {
+			t.Fatalf("invalid point (tau=%v, t=%v): got=%v, want=%v", tc.tau, tc.t, got, tc.pt)
+		}
+		if got := proc.CurveTangent(0, 1, 2, 3, tc.t); math.Abs(got-tc.tan) > tol {
+			t.Fatalf("invalid tangent (tau=%v, t=%v): got=%v, want=%v", tc.tau, tc.t, got, tc.tan)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gioui.org/f32"
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// flatTol is the tolerance, in pixels, of the approximation of curves with
+// line segments when computing the geometry of paths.
+const flatTol = 0.1
+
+// boolOp is a boolean operation between the areas of two paths.
+type boolOp uint8
+
+const (
+	boolUnion boolOp = iota
+	boolIntersect
+	boolDifference
+	boolXor
+)
+
+func (op boolOp) apply(a, b bool) bool {
+	switch op {
+	case boolUnion:
+		return a || b
+	case boolIntersect:
+		return a && b
+	case boolDifference:
+		return a && !b
+	case boolXor:
+		return a != b
+	default:
+		panic(fmt.Errorf("p5: invalid boolean operation %d", op))
+	}
+}
+
+// Union returns a new path enclosing the areas filled by either path.
+//
+// Curves are approximated with line segments. The returned path is
+// retained, as if created with NewPath, and uses the NonZero fill rule.
+func (p *Path) Union(other *Path) *Path {
+	return p.boolean(other, boolUnion)
+}
+
+// Intersect returns a new path enclosing the areas filled by both paths.
+//
+// Curves are approximated with line segments. The returned path is
+// retained, as if created with NewPath, and uses the NonZero fill rule.
+func (p *Path) Intersect(other *Path) *Path {
+	return p.boolean(other, boolIntersect)
+}
+
+// Difference returns a new path enclosing the areas filled by the path,
+// but not by the other one.
+//
+// Curves are approximated with line segments. The returned path is
+// retained, as if created with NewPath, and uses the NonZero fill rule.
+func (p *Path) Difference(other *Path) *Path {
+	return p.boolean(other, boolDifference)
+}
+
+// Xor returns a new path enclosing the areas filled by exactly one of
+// the paths.
+//
+// Curves are approximated with line segments. The returned path is
+// retained, as if created with NewPath, and uses the NonZero fill rule.
+func (p *Path) Xor(other *Path) *Path {
+	return p.boolean(other, boolXor)
+}
+
+// derive returns a new retained path, made of the provided segments and
+// sharing the coordinates system of p.
+func (p *Path) derive(segs segments) *Path {
+	return &Path{
+		proc:     p.proc,
+		s2uX:     p.s2uX,
+		s2uY:     p.s2uY,
+		u2sX:     p.u2sX,
+		u2sY:     p.u2sY,
+		kind:     ShapePolygon,
+		tau:      p.tau,
+		segs:     segs,
+		vtx:      len(segs),
+		retained: true,
+	}
+}
+
+// boolean returns the path enclosing the result of the op operation
+// between the areas of p and other.
+func (p *Path) boolean(other *Path, op boolOp) *Path {
+	var (
+		ea = p.edges()
+		eb = other.edges()
+	)
+	in := func(pt r2.Vec) bool {
+		return op.apply(p.rule.inside(windingEdges(ea, pt)), other.rule.inside(windingEdges(eb, pt)))
+	}
+	return p.derive(regionOutline(append(ea[:len(ea):len(ea)], eb...), in))
+}
+
+// regionOutline returns the outline of the region made of the points for
+// which in returns true, bounded by the es edges.
+//
+// Edges are cut at all their intersections. The resulting edges separating
+// a point of the region from a point outside of it make up the outline,
+// oriented for the non-zero rule.
+func regionOutline(es []edge, in func(pt r2.Vec) bool) segments {
+	es = splitEdges(es)
+	var (
+		keep = make([]edge, 0, len(es))
+		seen = make(map[edge]bool, len(es))
+	)
+
+	for _, e := range es {
+		var (
+			d   = r2.Sub(e.b, e.a)
+			n   = r2.Norm(d)
+			mid = r2.Scale(0.5, r2.Add(e.a, e.b))
+			off = r2.Scale(1e-3*math.Min(1, n)/n, r2.Vec{X: -d.Y, Y: d.X})
+			inL = in(r2.Add(mid, off))
+			inR = in(r2.Sub(mid, off))
+		)
+		switch {
+		case inL == inR:
+			continue
+		case inR:
+			e = edge{a: e.b, b: e.a}
+		}
+		// edges shared by both outlines only bound the result once.
+		if seen[e] {
+			continue
+		}
+		seen[e] = true
+		keep = append(keep, e)
+	}
+
+	var segs segments
+	for _, ps := range chainEdges(keep) {
+		ps = dropCollinear(ps)
+		if len(ps) < 3 {
+			continue
+		}
+		for i, pt := range ps {
+			pt := f32.Pt(float32(pt.X), float32(pt.Y))
+			if i == 0 {
+				segs = append(segs, opMoveTo(pt))
+				continue
+			}
+			segs = append(segs, opLineTo(pt))
+		}
+		segs = append(segs, segment{op: segOpClose})
+	}
+	return segs
+}
+
+// edge is an oriented line segment, in system coordinates.
+type edge struct {
+	a, b r2.Vec
+}
+
+// edges returns the edges of the closed, flattened outline of the path,
+// in system coordinates.
+func (p *Path) edges() []edge {
+	if !p.filled() {
+		return nil
+	}
+	return p.shape().edges()
+}
+
+// edges returns the edges of the closed, flattened contours of the
+// segments.
+func (segs segments) edges() []edge {
+	var (
+		lines = segs.transform(f32.Affine2D{}).polylines(flatTol)
+		o     []edge
+	)
+	for _, e := range edges(lines, true) {
+		var (
+			a = r2.Vec{X: float64(e[0].X), Y: float64(e[0].Y)}
+			b = r2.Vec{X: float64(e[1].X), Y: float64(e[1].Y)}
+		)
+		if a == b {
+			continue
+		}
+		o = append(o, edge{a: a, b: b})
+	}
+	return o
+}
+
+// splitEdges cuts the edges wherever they cross or touch another edge,
+// so that the returned edges only meet at their end points.
+func splitEdges(es []edge) []edge {
+	const tol = 1e-6
+
+	// on returns whether pt lies on the e edge, away from its end points.
+	on := func(e edge, pt r2.Vec) bool {
+		var (
+			d = r2.Sub(e.b, e.a)
+			n = r2.Norm(d)
+			t = r2.Dot(r2.Sub(pt, e.a), d) / (n * n)
+		)
+		return math.Abs(r2.Cross(d, r2.Sub(pt, e.a)))/n <= tol && t > 0 && t < 1
+	}
+
+	cuts := make([][]r2.Vec, len(es))
+	for i, e := range es {
+		for j := i + 1; j < len(es); j++ {
+			f := es[j]
+			if math.Max(e.a.X, e.b.X) < math.Min(f.a.X, f.b.X) ||
+				math.Max(f.a.X, f.b.X) < math.Min(e.a.X, e.b.X) ||
+				math.Max(e.a.Y, e.b.Y) < math.Min(f.a.Y, f.b.Y) ||
+				math.Max(f.a.Y, f.b.Y) < math.Min(e.a.Y, e.b.Y) {
+				continue
+			}
+
+			var (
+				r  = r2.Sub(e.b, e.a)
+				s  = r2.Sub(f.b, f.a)
+				rs = r2.Cross(r, s)
+				qp = r2.Sub(f.a, e.a)
+			)
+			if rs != 0 {
+				t := r2.Cross(qp, s) / rs
+				u := r2.Cross(qp, r) / rs
+				if t > 0 && t < 1 && u > 0 && u < 1 {
+					pt := r2.Add(e.a, r2.Scale(t, r))
+					cuts[i] = append(cuts[i], pt)
+					cuts[j] = append(cuts[j], pt)
+					continue
+				}
+			}
+
+			// end points touching, or overlapping, the other edge.
+			for _, pt := range []r2.Vec{f.a, f.b} {
+				if on(e, pt) {
+					cuts[i] = append(cuts[i], pt)
+				}
+			}
+			for _, pt := range []r2.Vec{e.a, e.b} {
+				if on(f, pt) {
+					cuts[j] = append(cuts[j], pt)
+				}
+			}
+		}
+	}
+
+	o := make([]edge, 0, len(es))
+	for i, e := range es {
+		pts := cuts[i]
+		if len(pts) == 0 {
+			o = append(o, e)
+			continue
+		}
+		d := r2.Sub(e.b, e.a)
+		sort.Slice(pts, func(i, j int) bool {
+			return r2.Dot(r2.Sub(pts[i], e.a), d) < r2.Dot(r2.Sub(pts[j], e.a), d)
+		})
+		beg := e.a
+		for _, pt := range append(pts, e.b) {
+			if pt == beg {
+				continue
+			}
+			o = append(o, edge{a: beg, b: pt})
+			beg = pt
+		}
+	}
+	return o
+}
+
+// windingEdges returns the winding number of the edges around pt.
+func windingEdges(es []edge, pt r2.Vec) int {
+	w := 0
+	for _, e := range es {
+		switch {
+		case e.a.Y <= pt.Y && e.b.Y > pt.Y:
+			if r2.Cross(r2.Sub(e.b, e.a), r2.Sub(pt, e.a)) > 0 {
+				w++
+			}
+		case e.a.Y > pt.Y && e.b.Y <= pt.Y:
+			if r2.Cross(r2.Sub(e.b, e.a), r2.Sub(pt, e.a)) < 0 {
+				w--
+			}
+		}
+	}
+	return w
+}
+
+// chainEdges joins the edges into closed polygons, by following each edge
+// with one starting at its end point.
+// The inside of the polygons must lie on the left of their edges, in a
+// y-up frame.
+func chainEdges(es []edge) [][]r2.Vec {
+	var (
+		from = make(map[r2.Vec][]int, len(es))
+		used = make([]bool, len(es))
+		o    [][]r2.Vec
+	)
+	for i, e := range es {
+		from[e.a] = append(from[e.a], i)
+	}
+
+	// next returns the unused edge following cur. Where several regions
+	// meet at a vertex, the edge turning the most towards the inside of
+	// the current region keeps polygons from crossing each other.
+	next := func(cur edge) int {
+		var (
+			din  = r2.Sub(cur.b, cur.a)
+			best = -1
+			max  = math.Inf(-1)
+		)
+		for _, i := range from[cur.b] {
+			if used[i] {
+				continue
+			}
+			dout := r2.Sub(es[i].b, es[i].a)
+			if turn := math.Atan2(r2.Cross(din, dout), r2.Dot(din, dout)); turn > max {
+				best = i
+				max = turn
+			}
+		}
+		return best
+	}
+
+	for i, e := range es {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		ps := []r2.Vec{e.a}
+		for cur := e; cur.b != e.a; {
+			ps = append(ps, cur.b)
+			j := next(cur)
+			if j < 0 {
+				// open chain, from rounding errors: close it.
+				break
+			}
+			used[j] = true
+			cur = es[j]
+		}
+		o = append(o, ps)
+	}
+	return o
+}
+
+// dropCollinear removes the vertices of the closed polygon lying on the
+// line joining their neighbours.
+func dropCollinear(ps []r2.Vec) []r2.Vec {
+	const tol = 1e-6
+	o := make([]r2.Vec, 0, len(ps))
+	for i, pt := range ps {
+		var (
+			prev = ps[(i+len(ps)-1)%len(ps)]
+			next = ps[(i+1)%len(ps)]
+			d    = r2.Sub(next, prev)
+			n    = r2.Norm(d)
+		)
+		if n > 0 && math.Abs(r2.Cross(d, r2.Sub(pt, prev)))/n <= tol &&
+			r2.Dot(r2.Sub(pt, prev), d) > 0 && r2.Dot(r2.Sub(next, pt), d) > 0 {
+			continue
+		}
+		o = append(o, pt)
+	}
+	return o
+}
@@ -0,0 +1,127 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPathBoolean(t *testing.T) {
+	proc := newProc(100, 100)
+	square := func(x, y, s float64) *Path {
+		p := proc.NewPath()
+		p.Vertex(x, y)
+		p.Vertex(x+s, y)
+		p.Vertex(x+s, y+s)
+		p.Vertex(x, y+s)
+		p.Close()
+		p.End()
+		return p
+	}
+	// a 20x20 square, with a 10x10 hole drawn in the same direction.
+	ring := func(x, y float64) *Path {
+		p := proc.NewPath()
+		p.FillRule(EvenOdd)
+		p.Vertex(x, y)
+		p.Vertex(x+20, y)
+		p.Vertex(x+20, y+20)
+		p.Vertex(x, y+20)
+		p.Close()
+		p.BeginContour()
+		p.Vertex(x+5, y+5)
+		p.Vertex(x+15, y+5)
+		p.Vertex(x+15, y+15)
+		p.Vertex(x+5, y+15)
+		p.EndContour()
+		p.End()
+		return p
+	}
+	area := func(p *Path) float64 {
+		return math.Abs(float64(p.shape().area()))
+	}
+
+	for _, tc := range []struct {
+		name string
+		path *Path
+		area float64
+		n    int // number of contours.
+	}{
+		{"union", square(0, 0, 20).Union(square(10, 10, 20)), 700, 1},
+		{"intersect", square(0, 0, 20).Intersect(square(10, 10, 20)), 100, 1},
+		{"difference", square(0, 0, 20).Difference(square(10, 10, 20)), 300, 1},
+		{"xor", square(0, 0, 20).Xor(square(10, 10, 20)), 600, 2},
+		{"disjoint", square(0, 0, 10).Union(square(20, 20, 10)), 200, 2},
+		{"empty", square(0, 0, 10).Intersect(square(20, 20, 10)), 0, 0},
+		{"shared-edge", square(0, 0, 10).Union(square(10, 0, 10)), 200, 1},
+		{"hole", square(0, 0, 30).Difference(square(10, 10, 10)), 800, 2},
+		{"ring-union", ring(0, 0).Union(square(5, 5, 5)), 400 - 75, 2},
+		{"ring-intersect", ring(0, 0).Intersect(square(0, 0, 10)), 100 - 25, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := len(tc.path.shape().contours()), tc.n; got != want {
+				t.Fatalf("invalid number of contours: got=%d, want=%d", got, want)
+			}
+			if got, want := area(tc.path), tc.area; math.Abs(got-want) > 1e-3 {
+				t.Fatalf("invalid area: got=%v, want=%v", got, want)
+			}
+			// all contours are oriented for the non-zero rule.
+			for _, c := range tc.path.shape().contours() {
+				if math.Abs(float64(c.area())) < 1e-3 {
+					t.Fatalf("degenerate contour: %v", c)
+				}
+			}
+		})
+	}
+
+	p := square(0, 0, 20).Difference(square(5, 5, 10))
+	for _, tc := range []struct {
+		x, y float64
+		want bool
+	}{
+		{2, 2, true},
+		{10, 10, false},
+		{25, 25, false},
+	} {
+		if got := p.Contains(tc.x, tc.y); got != tc.want {
+			t.Fatalf("invalid contains(%v, %v): got=%v, want=%v", tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestPathBooleanCurveVertex(t *testing.T) {
+	proc := newProc(100, 100)
+	proc.CurveTightness(1)
+	proc.BeginClip()
+
+	// a, once drawn, is no longer attached to proc.
+	a := proc.BeginPath()
+	a.Vertex(0, 0)
+	a.Vertex(20, 0)
+	a.Vertex(20, 20)
+	a.Close()
+	a.End()
+
+	b := proc.NewPath()
+	b.Vertex(0, 0)
+	b.Vertex(20, 20)
+	b.Vertex(0, 20)
+	b.Close()
+	b.End()
+
+	p := a.Union(b)
+	p.BeginContour()
+	for _, v := range [][2]float64{{40, 40}, {50, 40}, {50, 50}, {40, 50}, {40, 40}, {50, 40}, {50, 50}} {
+		p.CurveVertex(v[0], v[1])
+	}
+	p.EndContour()
+
+	if got, want := len(p.shape().contours()), 2; got != want {
+		t.Fatalf("invalid number of contours: got=%d, want=%d", got, want)
+	}
+	if got, want := math.Abs(float64(p.shape().area())), 400+100.0; math.Abs(got-want) > 1e-3 {
+		t.Fatalf("invalid area: got=%v, want=%v", got, want)
+	}
+}
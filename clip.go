@@ -0,0 +1,83 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"gioui.org/f32"
+)
+
+// clipMask records the shapes making up a clipping mask.
+type clipMask struct {
+	invert bool
+	shapes []segments // shapes, in canvas coordinates.
+}
+
+// add adds the area enclosed by path, expressed in the coordinates of
+// the m transformation, to the mask.
+func (mask *clipMask) add(path segments, m f32.Affine2D) {
+	path = path.transform(m)
+	// orient all shapes the same way, so overlapping shapes
+	// do not cancel each other out under the non-zero rule.
+	if path.area() < 0 {
+		path = path.reverse()
+	}
+	mask.shapes = append(mask.shapes, path)
+}
+
+// BeginClip starts recording a clipping mask.
+//
+// Shapes drawn until EndClip are not painted: the areas they enclose are
+// combined into a mask that restricts all subsequent drawing, until the
+// current context is restored with Pop.
+// Styles are ignored while recording a mask; strokes, lines, text and
+// images do not contribute to it.
+func (p *Proc) BeginClip() {
+	p.stk.mask = &clipMask{}
+}
+
+// BeginInvertedClip starts recording an inverted clipping mask.
+//
+// BeginInvertedClip behaves like BeginClip, except that drawing is
+// restricted to the area outside of the recorded shapes.
+func (p *Proc) BeginInvertedClip() {
+	p.stk.mask = &clipMask{invert: true}
+}
+
+// EndClip stops recording the clipping mask and applies it.
+func (p *Proc) EndClip() {
+	mask := p.stk.mask
+	if mask == nil {
+		return
+	}
+	p.stk.mask = nil
+
+	// shapes were recorded in canvas coordinates, as expected by
+	// the clips of the stack.
+	if !mask.invert {
+		var area segments
+		for _, shape := range mask.shapes {
+			area = append(area, shape...)
+		}
+		p.stk.clip(area)
+		return
+	}
+
+	// the complement of the union of all shapes is the intersection
+	// of their complements, which Gio clips compute.
+	var (
+		w, h = p.cnvSize()
+		cnv  = segments{
+			opMoveTo(f32.Pt(0, 0)),
+			opLineTo(f32.Pt(float32(w), 0)),
+			opLineTo(f32.Pt(float32(w), float32(h))),
+			opLineTo(f32.Pt(0, float32(h))),
+			{op: segOpClose},
+		}
+	)
+	for _, shape := range mask.shapes {
+		area := append(cnv[:len(cnv):len(cnv)], shape.reverse()...)
+		p.stk.clip(area)
+	}
+}
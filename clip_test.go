@@ -0,0 +1,91 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"math"
+	"testing"
+
+	"gioui.org/f32"
+)
+
+func TestClipMaskOrientation(t *testing.T) {
+	var (
+		cw = segments{
+			opMoveTo(f32.Pt(0, 0)),
+			opLineTo(f32.Pt(0, 10)),
+			opLineTo(f32.Pt(10, 10)),
+			opLineTo(f32.Pt(10, 0)),
+			{op: segOpClose},
+		}
+		mask clipMask
+	)
+
+	if got, want := cw.area(), float32(-100); got != want {
+		t.Fatalf("invalid area: got=%v, want=%v", got, want)
+	}
+	if got, want := cw.reverse().area(), float32(+100); got != want {
+		t.Fatalf("invalid reversed area: got=%v, want=%v", got, want)
+	}
+
+	mask.add(cw, f32.Affine2D{}.Offset(f32.Pt(5, 5)))
+	if got, want := len(mask.shapes), 1; got != want {
+		t.Fatalf("invalid number of shapes: got=%d, want=%d", got, want)
+	}
+	shape := mask.shapes[0]
+	if got, want := shape.area(), float32(+100); got != want {
+		t.Fatalf("invalid mask area: got=%v, want=%v", got, want)
+	}
+	if got, want := shape[0].args[0], f32.Pt(15, 5); got != want {
+		t.Fatalf("invalid mask start: got=%v, want=%v", got, want)
+	}
+
+	circle := segments{
+		opMoveTo(f32.Pt(10, 0)),
+		opArcTo(f32.Pt(0, 0), f32.Pt(0, 0), 2*math.Pi),
+	}.transform(f32.Affine2D{})
+	if got, want := float64(circle.area()), math.Pi*100; math.Abs(got-want) > 0.5 {
+		t.Fatalf("invalid circle area: got=%v, want=%v", got, want)
+	}
+}
+
+func TestClipStack(t *testing.T) {
+	p := newProc(200, 200)
+
+	p.BeginClip()
+	p.Rect(10, 10, 50, 50)
+	p.Line(0, 0, 100, 100)
+	p.Text("no text", 10, 10)
+	if got, want := len(p.stk.mask.shapes), 1; got != want {
+		t.Fatalf("invalid number of recorded shapes: got=%d, want=%d", got, want)
+	}
+	p.EndClip()
+	if p.stk.mask != nil {
+		t.Fatalf("mask still recording")
+	}
+	if got, want := len(p.stk.cur().clips), 1; got != want {
+		t.Fatalf("invalid number of clips: got=%d, want=%d", got, want)
+	}
+
+	p.Push()
+	p.Translate(20, 20)
+	p.BeginInvertedClip()
+	p.Circle(50, 50, 20)
+	p.Ellipse(80, 50, 20, 10)
+	p.EndClip()
+	if got, want := len(p.stk.cur().clips), 2; got != want {
+		t.Fatalf("invalid number of inverted clips: got=%d, want=%d", got, want)
+	}
+	p.Pop()
+
+	if got, want := len(p.stk.cur().clips), 1; got != want {
+		t.Fatalf("invalid number of clips after pop: got=%d, want=%d", got, want)
+	}
+
+	p.stk.reset()
+	if got, want := len(p.stk.cur().clips), 0; got != want {
+		t.Fatalf("invalid number of clips after reset: got=%d, want=%d", got, want)
+	}
+}
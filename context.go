@@ -10,14 +10,15 @@ import (
 	"gioui.org/f32"
 	"gioui.org/font"
 	"gioui.org/op"
-	"gioui.org/text"
+	"gioui.org/op/clip"
 	"gioui.org/x/stroke"
 )
 
 // stackOps holds a stack of Gio operations and state.
 type stackOps struct {
-	ops *op.Ops
-	ctx []context
+	ops  *op.Ops
+	ctx  []context
+	mask *clipMask // clipping mask being recorded, if any.
 }
 
 func newStackOps(ops *op.Ops) *stackOps {
@@ -36,6 +37,13 @@ type context struct {
 
 	tau float32 // Catmull-Rom tension, used for Curve.
 
+	rectMode    ShapeMode // interpretation of Rect parameters.
+	ellipseMode ShapeMode // interpretation of Ellipse and Arc parameters.
+	imageMode   ShapeMode // interpretation of DrawImage parameters.
+
+	aff   f32.Affine2D // current transformation, relative to the canvas.
+	clips []clip.Stack // clipping masks applied within this context.
+	areas []segments   // areas of the clipping masks, in canvas coordinates.
 	state op.TransformStack
 }
 
@@ -50,10 +58,13 @@ type strokeStyle struct {
 }
 
 type textStyle struct {
-	color color.Color
-	align text.Alignment
-	size  float32
-	font  font.Font
+	color   color.Color   // fill color of glyphs, nil for none.
+	stroke  color.Color   // outline color of glyphs, nil for none.
+	halign  TextAlignment // horizontal alignment.
+	valign  TextAlignment // vertical alignment.
+	size    float32
+	leading float32 // spacing between baselines, zero for the default.
+	font    font.Font
 }
 
 func (stk *stackOps) cur() *context {
@@ -62,41 +73,102 @@ func (stk *stackOps) cur() *context {
 
 func (stk *stackOps) push() {
 	stk.ctx = append(stk.ctx, *stk.cur())
+	stk.cur().clips = nil
+	stk.cur().areas = nil
 	stk.cur().state = op.TransformOp{}.Push(stk.ops)
 }
 
 func (stk *stackOps) pop() {
+	stk.unclip()
 	stk.cur().state.Pop()
 	stk.ctx = stk.ctx[:len(stk.ctx)-1]
 }
 
+// reset discards the state that does not outlive a frame:
+// transformations, clipping masks and masks being recorded.
+func (stk *stackOps) reset() {
+	for i := len(stk.ctx) - 1; i >= 0; i-- {
+		clips := stk.ctx[i].clips
+		for j := len(clips) - 1; j >= 0; j-- {
+			clips[j].Pop()
+		}
+		stk.ctx[i].clips = nil
+		stk.ctx[i].areas = nil
+	}
+	stk.ctx[0].aff = f32.Affine2D{}
+	stk.mask = nil
+}
+
+// clip restricts drawing within the current context to the provided area,
+// in canvas coordinates.
+func (stk *stackOps) clip(area segments) {
+	ctx := stk.cur()
+	ctx.areas = append(ctx.areas, area)
+	ctx.clips = append(ctx.clips, stk.pushClip(area, ctx.aff))
+}
+
+// pushClip pushes the area, in canvas coordinates, as a clipping mask
+// under the aff transformation.
+func (stk *stackOps) pushClip(area segments, aff f32.Affine2D) clip.Stack {
+	defer op.Affine(aff.Invert()).Push(stk.ops).Pop()
+	return area.outline(stk.ops).Push(stk.ops)
+}
+
+// unclip removes the clipping masks applied within the current context.
+func (stk *stackOps) unclip() {
+	clips := stk.cur().clips
+	for i := len(clips) - 1; i >= 0; i-- {
+		clips[i].Pop()
+	}
+	stk.cur().clips = nil
+	stk.cur().areas = nil
+}
+
+// rebuild pushes the transformations and clipping masks of all contexts
+// anew, once the operations have been reset.
+func (stk *stackOps) rebuild() {
+	var prev f32.Affine2D
+	for i := range stk.ctx {
+		ctx := &stk.ctx[i]
+		if i > 0 {
+			ctx.state = op.TransformOp{}.Push(stk.ops)
+		}
+		op.Affine(prev.Invert().Mul(ctx.aff)).Add(stk.ops)
+		for j, area := range ctx.areas {
+			ctx.clips[j] = stk.pushClip(area, ctx.aff)
+		}
+		prev = ctx.aff
+	}
+}
+
 func (stk *stackOps) rotate(angle float64) {
-	op.Affine(f32.Affine2D{}.Rotate(
+	stk.matrix(f32.Affine2D{}.Rotate(
 		f32.Pt(0, 0), float32(-angle),
-	)).Add(stk.ops)
+	))
 }
 
 func (stk *stackOps) scale(x, y float64) {
-	op.Affine(f32.Affine2D{}.Scale(
+	stk.matrix(f32.Affine2D{}.Scale(
 		f32.Pt(0, 0),
 		f32.Pt(float32(x), float32(y)),
-	)).Add(stk.ops)
+	))
 }
 
 func (stk *stackOps) translate(x, y float64) {
-	op.Affine(f32.Affine2D{}.Offset(
+	stk.matrix(f32.Affine2D{}.Offset(
 		f32.Pt(float32(x), float32(y)),
-	)).Add(stk.ops)
+	))
 }
 
 func (stk *stackOps) shear(x, y float64) {
-	op.Affine(f32.Affine2D{}.Shear(
+	stk.matrix(f32.Affine2D{}.Shear(
 		f32.Pt(0, 0),
 		float32(x), float32(y),
-	)).Add(stk.ops)
+	))
 }
 
 func (stk *stackOps) matrix(aff f32.Affine2D) {
+	stk.cur().aff = stk.cur().aff.Mul(aff)
 	op.Affine(aff).Add(stk.ops)
 }
 
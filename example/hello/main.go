@@ -58,7 +58,8 @@ func draw() {
 
 	p5.Stroke(color.Black)
 	p5.StrokeWidth(5)
-	p5.Arc(300, 100, 80, 20, 0, 1.5*math.Pi)
+	p5.Fill(nil)
+	p5.Arc(300, 100, 160, 40, 0, 1.5*math.Pi, p5.ArcOpen)
 }
 
 func loadFonts() {
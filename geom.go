@@ -0,0 +1,384 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"math"
+
+	"gioui.org/f32"
+	bstroke "github.com/andybalholm/stroke"
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// polylines returns the flattened contours of the path, in user coordinates.
+func (p *Path) polylines() [][]f32.Point {
+	return p.user().polylines(0)
+}
+
+// user returns the segments of the path, in user coordinates.
+// Arcs are converted to cubic Bézier curves.
+func (p *Path) user() segments {
+	return p.shape().transform(f32.Affine2D{}).apply(func(pt f32.Point) f32.Point {
+		return f32.Pt(
+			float32(p.s2uX(float64(pt.X))),
+			float32(p.s2uY(float64(pt.Y))),
+		)
+	})
+}
+
+// system returns the segments, in user coordinates, converted to system
+// coordinates.
+func (p *Path) system(segs segments) segments {
+	return segs.apply(func(pt f32.Point) f32.Point {
+		return f32.Pt(
+			float32(p.u2sX(float64(pt.X))),
+			float32(p.u2sY(float64(pt.Y))),
+		)
+	})
+}
+
+// filled returns whether the path encloses an area.
+func (p *Path) filled() bool {
+	return p.kind != ShapePoints && p.kind != ShapeLines
+}
+
+// Bounds returns the bounding box of the path, in user coordinates.
+//
+// Curves are approximated with line segments.
+func (p *Path) Bounds() r2.Box {
+	var (
+		box   r2.Box
+		first = true
+	)
+	for _, ps := range p.polylines() {
+		for _, pt := range ps {
+			var (
+				x = float64(pt.X)
+				y = float64(pt.Y)
+			)
+			if first {
+				box = r2.Box{Min: r2.Vec{X: x, Y: y}, Max: r2.Vec{X: x, Y: y}}
+				first = false
+				continue
+			}
+			box.Min.X = math.Min(box.Min.X, x)
+			box.Min.Y = math.Min(box.Min.Y, y)
+			box.Max.X = math.Max(box.Max.X, x)
+			box.Max.Y = math.Max(box.Max.Y, y)
+		}
+	}
+	return box
+}
+
+// Length returns the length of the path, in user coordinates.
+//
+// Closed sub-paths include the line closing them; the gaps between
+// sub-paths do not count towards the length.
+func (p *Path) Length() float64 {
+	var length float64
+	for _, ps := range p.polylines() {
+		for i := 1; i < len(ps); i++ {
+			length += dist(ps[i-1], ps[i])
+		}
+	}
+	return length
+}
+
+// Contains returns whether the (x,y) point, in user coordinates, lies
+// inside the area filled by the path, according to its fill rule.
+//
+// Points and lines enclose no area and contain no point.
+func (p *Path) Contains(x, y float64) bool {
+	if !p.filled() {
+		return false
+	}
+	return p.contains(p.polylines(), f32.Pt(float32(x), float32(y)))
+}
+
+func (p *Path) contains(lines [][]f32.Point, pt f32.Point) bool {
+	w := 0
+	for _, ps := range lines {
+		w += winding(ps, pt)
+	}
+	return p.rule.inside(w)
+}
+
+// Intersects returns whether the path and the other one overlap.
+//
+// Filled paths overlap when their areas share a point, which includes
+// a path enclosing the other one. Lines overlap when they cross another
+// line or lie inside a filled area.
+func (p *Path) Intersects(other *Path) bool {
+	var (
+		a = p.polylines()
+		b = other.polylines()
+	)
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+
+	ba := p.Bounds()
+	bb := other.Bounds()
+	if ba.Max.X < bb.Min.X || bb.Max.X < ba.Min.X ||
+		ba.Max.Y < bb.Min.Y || bb.Max.Y < ba.Min.Y {
+		return false
+	}
+
+	ea := edges(a, p.filled())
+	eb := edges(b, other.filled())
+	for _, e := range ea {
+		for _, f := range eb {
+			if crosses(e[0], e[1], f[0], f[1]) {
+				return true
+			}
+		}
+	}
+
+	// no edges cross: each contour lies either entirely inside the other
+	// path, or outside of it.
+	if other.filled() {
+		for _, c := range a {
+			if other.contains(b, c[0]) {
+				return true
+			}
+		}
+	}
+	if p.filled() {
+		for _, c := range b {
+			if p.contains(a, c[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Flatten returns the contours of the path as polylines, in user
+// coordinates, approximating curves with line segments within the tol
+// distance.
+//
+// Closed contours end with their starting point.
+func (p *Path) Flatten(tol float64) [][]r2.Vec {
+	var (
+		lines = p.user().polylines(float32(tol))
+		o     = make([][]r2.Vec, len(lines))
+	)
+	for i, ps := range lines {
+		o[i] = make([]r2.Vec, len(ps))
+		for j, pt := range ps {
+			o[i][j] = r2.Vec{X: float64(pt.X), Y: float64(pt.Y)}
+		}
+	}
+	return o
+}
+
+// Simplify returns a new path approximating the path with fewer vertices,
+// within the tol distance, in user coordinates.
+//
+// Curves are approximated with line segments, which are then simplified
+// with the Ramer–Douglas–Peucker algorithm. The returned path is retained,
+// as if created with NewPath, and keeps the fill rule of the path.
+func (p *Path) Simplify(tol float64) *Path {
+	var segs segments
+	for _, ps := range p.user().polylines(float32(tol / 2)) {
+		closed := len(ps) > 2 && ps[0] == ps[len(ps)-1]
+		ps = rdp(ps, tol/2)
+		if closed {
+			ps = ps[:len(ps)-1]
+		}
+		for i, pt := range ps {
+			if i == 0 {
+				segs = append(segs, opMoveTo(pt))
+				continue
+			}
+			segs = append(segs, opLineTo(pt))
+		}
+		if closed {
+			segs = append(segs, segment{op: segOpClose})
+		}
+	}
+
+	o := p.derive(p.system(segs))
+	o.rule = p.rule
+	return o
+}
+
+// rdp simplifies the ps polyline with the Ramer–Douglas–Peucker algorithm,
+// keeping the removed vertices within the tol distance of the result.
+func rdp(ps []f32.Point, tol float64) []f32.Point {
+	if len(ps) < 3 {
+		return ps
+	}
+	var (
+		a, b = ps[0], ps[len(ps)-1]
+		imax = 0
+		dmax = -1.0
+	)
+	for i := 1; i < len(ps)-1; i++ {
+		if d := segDist(a, b, ps[i]); d > dmax {
+			imax, dmax = i, d
+		}
+	}
+	if dmax <= tol {
+		return []f32.Point{a, b}
+	}
+	var (
+		lhs = rdp(ps[:imax+1], tol)
+		rhs = rdp(ps[imax:], tol)
+	)
+	return append(lhs[:len(lhs)-1:len(lhs)-1], rhs...)
+}
+
+// segDist returns the distance between pt and the a-b line segment.
+func segDist(a, b, pt f32.Point) float64 {
+	var (
+		d  = b.Sub(a)
+		n2 = float64(d.X*d.X + d.Y*d.Y)
+	)
+	if n2 == 0 {
+		return dist(a, pt)
+	}
+	t := float64(pt.Sub(a).X*d.X+pt.Sub(a).Y*d.Y) / n2
+	t = math.Max(0, math.Min(1, t))
+	return dist(a.Add(d.Mul(float32(t))), pt)
+}
+
+// Offset returns a new path whose outline lies at the d distance from the
+// outline of the path, in user coordinates.
+// Positive distances grow the filled area, negative ones shrink it.
+// Paths enclosing no area, such as lines, are outlined on both sides.
+//
+// Curves are approximated with line segments. The returned path is
+// retained, as if created with NewPath, and uses the NonZero fill rule.
+func (p *Path) Offset(d float64) *Path {
+	var (
+		r      = float32(math.Abs(d))
+		filled = p.filled()
+		band   segments // outline of the band around the contours.
+	)
+	if r > 0 {
+		var contours [][]bstroke.Segment
+		for _, ps := range p.user().polylines(p.userTol()) {
+			if filled && ps[0] != ps[len(ps)-1] {
+				ps = append(ps, ps[0])
+			}
+			var c []bstroke.Segment
+			for i := 1; i < len(ps); i++ {
+				if ps[i-1] == ps[i] {
+					continue
+				}
+				c = append(c, bstroke.LinearSegment(bstroke.Point(ps[i-1]), bstroke.Point(ps[i])))
+			}
+			if len(c) > 0 {
+				contours = append(contours, c)
+			}
+		}
+
+		// closed contours are stroked as 2 outlines of opposite
+		// directions, so the band is filled with the non-zero rule.
+		opt := bstroke.Options{Width: 2 * r, Cap: bstroke.RoundCap, Join: bstroke.RoundJoin}
+		for _, c := range bstroke.Stroke(contours, opt) {
+			band = append(band, opMoveTo(f32.Point(c[0].Start)))
+			for _, s := range c {
+				band = append(band, opCubeTo(f32.Point(s.CP1), f32.Point(s.CP2), f32.Point(s.End)))
+			}
+			band = append(band, segment{op: segOpClose})
+		}
+	}
+
+	outline := p.derive(p.system(band))
+	switch {
+	case !filled:
+		return outline.Union(p.derive(nil))
+	case d < 0:
+		return p.Difference(outline)
+	default:
+		return p.Union(outline)
+	}
+}
+
+// userTol returns the tolerance of the approximation of curves with line
+// segments, in user coordinates.
+func (p *Path) userTol() float32 {
+	var (
+		sx = math.Abs(p.u2sX(1) - p.u2sX(0))
+		sy = math.Abs(p.u2sY(1) - p.u2sY(0))
+	)
+	return float32(flatTol / math.Max(sx, sy))
+}
+
+// winding returns the winding number of the closed polygon ps around pt.
+func winding(ps []f32.Point, pt f32.Point) int {
+	w := 0
+	for i, p := range ps {
+		q := ps[(i+1)%len(ps)]
+		switch {
+		case p.Y <= pt.Y && q.Y > pt.Y:
+			if orient(p, q, pt) > 0 {
+				w++
+			}
+		case p.Y > pt.Y && q.Y <= pt.Y:
+			if orient(p, q, pt) < 0 {
+				w--
+			}
+		}
+	}
+	return w
+}
+
+// orient returns a positive value when p, q and r turn counter-clockwise
+// in a y-up frame, a negative value when they turn clockwise and zero
+// when they are collinear.
+func orient(p, q, r f32.Point) float64 {
+	return float64(q.X-p.X)*float64(r.Y-p.Y) - float64(r.X-p.X)*float64(q.Y-p.Y)
+}
+
+// edges returns the line segments making up the polylines.
+// Closed polylines include the line joining their ends.
+func edges(lines [][]f32.Point, closed bool) [][2]f32.Point {
+	var o [][2]f32.Point
+	for _, ps := range lines {
+		for i := 1; i < len(ps); i++ {
+			o = append(o, [2]f32.Point{ps[i-1], ps[i]})
+		}
+		if closed && len(ps) > 1 && ps[0] != ps[len(ps)-1] {
+			o = append(o, [2]f32.Point{ps[len(ps)-1], ps[0]})
+		}
+	}
+	return o
+}
+
+// crosses returns whether the line segments p1-p2 and q1-q2 share a point.
+func crosses(p1, p2, q1, q2 f32.Point) bool {
+	var (
+		d1 = orient(q1, q2, p1)
+		d2 = orient(q1, q2, p2)
+		d3 = orient(p1, p2, q1)
+		d4 = orient(p1, p2, q2)
+	)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	// collinear end points.
+	on := func(p, q, r f32.Point) bool {
+		return math.Min(float64(p.X), float64(q.X)) <= float64(r.X) &&
+			float64(r.X) <= math.Max(float64(p.X), float64(q.X)) &&
+			math.Min(float64(p.Y), float64(q.Y)) <= float64(r.Y) &&
+			float64(r.Y) <= math.Max(float64(p.Y), float64(q.Y))
+	}
+	switch {
+	case d1 == 0 && on(q1, q2, p1):
+		return true
+	case d2 == 0 && on(q1, q2, p2):
+		return true
+	case d3 == 0 && on(p1, p2, q1):
+		return true
+	case d4 == 0 && on(p1, p2, q2):
+		return true
+	}
+	return false
+}
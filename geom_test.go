@@ -0,0 +1,290 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func TestPathGeometry(t *testing.T) {
+	proc := newProc(200, 200)
+	proc.PhysCanvas(200, 200, -10, 10, -10, 10)
+
+	// a square ring, made of an outline and a hole drawn the same way.
+	ring := func(rule FillRule) *Path {
+		p := proc.BeginPath()
+		p.FillRule(rule)
+		p.Vertex(-4, -4)
+		p.Vertex(+4, -4)
+		p.Vertex(+4, +4)
+		p.Vertex(-4, +4)
+		p.Close()
+		p.BeginContour()
+		p.Vertex(-2, -2)
+		p.Vertex(+2, -2)
+		p.Vertex(+2, +2)
+		p.Vertex(-2, +2)
+		p.EndContour()
+		p.End()
+		return p
+	}
+
+	p := ring(EvenOdd)
+	if got, want := p.Bounds(), (r2.Box{Min: r2.Vec{X: -4, Y: -4}, Max: r2.Vec{X: 4, Y: 4}}); got != want {
+		t.Fatalf("invalid bounds: got=%v, want=%v", got, want)
+	}
+	if got, want := p.Length(), 8*4.0+4*4.0; math.Abs(got-want) > 1e-4 {
+		t.Fatalf("invalid length: got=%v, want=%v", got, want)
+	}
+
+	for _, tc := range []struct {
+		rule FillRule
+		x, y float64
+		want bool
+	}{
+		{NonZero, 0, 0, true},
+		{NonZero, 3, 0, true},
+		{NonZero, 5, 0, false},
+		{EvenOdd, 0, 0, false},
+		{EvenOdd, 3, 0, true},
+		{EvenOdd, 0, -3, true},
+		{EvenOdd, 5, 0, false},
+	} {
+		if got := ring(tc.rule).Contains(tc.x, tc.y); got != tc.want {
+			t.Fatalf("invalid contains(rule=%d, x=%v, y=%v): got=%v, want=%v", tc.rule, tc.x, tc.y, got, tc.want)
+		}
+	}
+
+	circle := func(x, y, r float64) *Path {
+		p := proc.BeginPath()
+		p.Arc(x, y, r, r, 0, 2*math.Pi)
+		p.Close()
+		p.End()
+		return p
+	}
+	// squares returns a path with a contour for each of the squares, given
+	// by their top-left corner and size.
+	squares := func(sqs ...[3]float64) *Path {
+		p := proc.BeginPath()
+		for _, sq := range sqs {
+			x, y, w := sq[0], sq[1], sq[2]
+			p.BeginContour()
+			p.Vertex(x, y)
+			p.Vertex(x+w, y)
+			p.Vertex(x+w, y+w)
+			p.Vertex(x, y+w)
+			p.EndContour()
+		}
+		p.Close()
+		p.End()
+		return p
+	}
+	line := func(x1, y1, x2, y2 float64) *Path {
+		p := proc.BeginShape(ShapeLines)
+		p.Vertex(x1, y1)
+		p.Vertex(x2, y2)
+		p.End()
+		return p
+	}
+
+	if got, want := circle(0, 0, 1).Length(), 2*math.Pi; math.Abs(got-want) > 1e-2 {
+		t.Fatalf("invalid circle length: got=%v, want=%v", got, want)
+	}
+
+	for _, tc := range []struct {
+		name string
+		a, b *Path
+		want bool
+	}{
+		{"overlap", circle(0, 0, 2), circle(3, 0, 2), true},
+		{"disjoint", circle(0, 0, 2), circle(5, 0, 2), false},
+		{"nested", circle(0, 0, 1), circle(0, 0, 5), true},
+		{"hole", circle(0, 0, 1), ring(EvenOdd), false},
+		{"ring", circle(3, 0, 0.5), ring(EvenOdd), true},
+		{"line-cross", line(-5, 0, 5, 0), circle(0, 0, 2), true},
+		{"line-inside", line(-1, 0, 1, 0), circle(0, 0, 2), true},
+		{"lines", line(-1, -1, 1, 1), line(-1, 1, 1, -1), true},
+		{"lines-disjoint", line(-1, -1, 1, -1), line(-1, 1, 1, 1), false},
+		{"contours-nested", squares([3]float64{7, 7, 2}, [3]float64{-1, -1, 2}), squares([3]float64{-5, -5, 10}), true},
+		{"contours-disjoint", squares([3]float64{7, 7, 2}, [3]float64{-9, -9, 2}), squares([3]float64{-5, -5, 10}), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.Intersects(tc.b); got != tc.want {
+				t.Fatalf("invalid a/b intersection: got=%v, want=%v", got, tc.want)
+			}
+			if got := tc.b.Intersects(tc.a); got != tc.want {
+				t.Fatalf("invalid b/a intersection: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPathGeometryCanvas(t *testing.T) {
+	proc := newProc(200, 200)
+	proc.PhysCanvas(200, 200, -10, 10, -10, 10)
+
+	p := proc.BeginPath()
+	p.Vertex(-4, -4)
+	p.Vertex(+4, -4)
+	p.Vertex(+4, +4)
+	p.Vertex(-4, +4)
+	p.Close()
+	p.End()
+
+	// a path keeps the mapping of the canvas it was built on.
+	proc.PhysCanvas(200, 200, 0, 20, 0, 20)
+
+	if got, want := p.Bounds(), (r2.Box{Min: r2.Vec{X: -4, Y: -4}, Max: r2.Vec{X: 4, Y: 4}}); got != want {
+		t.Fatalf("invalid bounds: got=%v, want=%v", got, want)
+	}
+	if !p.Contains(0, 0) || p.Contains(10, 10) {
+		t.Fatalf("invalid containment after canvas change")
+	}
+}
+
+func TestPathFlatten(t *testing.T) {
+	proc := newProc(200, 200)
+	p := proc.NewPath()
+	p.Arc(50, 50, 10, 10, 0, 2*math.Pi)
+	p.Close()
+	p.End()
+
+	for _, tol := range []float64{1, 0.1, 0.01} {
+		lines := p.Flatten(tol)
+		if got, want := len(lines), 1; got != want {
+			t.Fatalf("invalid number of polylines: got=%d, want=%d", got, want)
+		}
+		ps := lines[0]
+		if ps[0] != ps[len(ps)-1] {
+			t.Fatalf("polyline not closed (tol=%v)", tol)
+		}
+		for i := 1; i < len(ps); i++ {
+			mid := r2.Scale(0.5, r2.Add(ps[i-1], ps[i]))
+			if d := 10 - r2.Norm(r2.Sub(mid, r2.Vec{X: 50, Y: 50})); d > tol+1e-3 {
+				t.Fatalf("invalid flattening (tol=%v): distance=%v", tol, d)
+			}
+		}
+	}
+	if a, b := len(p.Flatten(1)[0]), len(p.Flatten(0.01)[0]); a >= b {
+		t.Fatalf("invalid number of vertices: tol=1: %d, tol=0.01: %d", a, b)
+	}
+}
+
+func TestPathSimplify(t *testing.T) {
+	proc := newProc(200, 200)
+	p := proc.NewPath()
+	// a square, with slightly jittered vertices along its edges.
+	for i := 0; i < 40; i++ {
+		var (
+			j = 0.05 * float64(i%2)
+			s = float64(i%10) * 10
+		)
+		switch i / 10 {
+		case 0:
+			p.Vertex(s, j)
+		case 1:
+			p.Vertex(100+j, s)
+		case 2:
+			p.Vertex(100-s, 100+j)
+		case 3:
+			p.Vertex(j, 100-s)
+		}
+	}
+	p.Close()
+	p.End()
+
+	s := p.Simplify(0.5)
+	if got, want := len(s.segs), 4+1; got != want {
+		t.Fatalf("invalid number of segments: got=%d, want=%d", got, want)
+	}
+	if got, want := s.shape().area(), p.shape().area(); math.Abs(float64(got-want)) > 10 {
+		t.Fatalf("invalid area: got=%v, want=%v", got, want)
+	}
+}
+
+func TestPathOffset(t *testing.T) {
+	proc := newProc(200, 200)
+	square := proc.NewPath()
+	square.Vertex(50, 50)
+	square.Vertex(70, 50)
+	square.Vertex(70, 70)
+	square.Vertex(50, 70)
+	square.Close()
+	square.End()
+
+	line := proc.BeginShape(ShapeLines)
+	line.Vertex(50, 50)
+	line.Vertex(70, 50)
+	line.End()
+
+	circle := proc.NewPath()
+	circle.Arc(100, 100, 40, 40, 0, 2*math.Pi)
+	circle.Close()
+	circle.End()
+
+	// a regular polygon with many edges: its offset area follows from its
+	// perimeter and its apothem.
+	const (
+		n  = 64
+		rp = 40.0
+	)
+	poly := proc.NewPath()
+	for i := 0; i < n; i++ {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / n)
+		poly.Vertex(100+rp*cos, 100+rp*sin)
+	}
+	poly.Close()
+	poly.End()
+	var (
+		side    = 2 * rp * math.Sin(math.Pi/n)
+		apothem = rp * math.Cos(math.Pi/n)
+		area    = 0.5 * n * side * apothem
+	)
+
+	for _, tc := range []struct {
+		name string
+		path *Path
+		area float64
+	}{
+		{"outset", square.Offset(5), 20*20 + 4*20*5 + math.Pi*5*5},
+		{"inset", square.Offset(-5), 10 * 10},
+		{"vanish", square.Offset(-15), 0},
+		{"line", line.Offset(5), 20*10 + math.Pi*5*5},
+		{"circle-outset", circle.Offset(5), math.Pi * 45 * 45},
+		{"circle-inset", circle.Offset(-5), math.Pi * 35 * 35},
+		{"poly-outset", poly.Offset(5), area + n*side*5 + math.Pi*5*5},
+		{"poly-inset", poly.Offset(-5), area * (apothem - 5) * (apothem - 5) / (apothem * apothem)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// curves are flattened within a tenth of a pixel.
+			got := math.Abs(float64(tc.path.shape().area()))
+			if math.Abs(got-tc.area) > math.Max(2, 5e-3*tc.area) {
+				t.Fatalf("invalid area: got=%v, want=%v", got, tc.area)
+			}
+		})
+	}
+}
+
+func BenchmarkPathOffset(b *testing.B) {
+	proc := newProc(200, 200)
+	p := proc.NewPath()
+	for i := 0; i < 200; i++ {
+		var (
+			r        = 40 + 20*float64(i%2)
+			sin, cos = math.Sincos(2 * math.Pi * float64(i) / 200)
+		)
+		p.Vertex(100+r*cos, 100+r*sin)
+	}
+	p.Close()
+	p.End()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Offset(2)
+	}
+}
@@ -8,6 +8,7 @@ require (
 	github.com/andybalholm/stroke v0.0.0-20230904101225-24ef450bc62c
 	github.com/campoy/embedmd v1.0.0
 	github.com/go-fonts/latin-modern v0.3.0
+	github.com/go-text/typesetting v0.3.0
 	golang.org/x/exp v0.0.0-20250718183923-645b1fa84792
 	golang.org/x/image v0.29.0
 	golang.org/x/tools v0.35.0
@@ -17,7 +18,6 @@ require (
 
 require (
 	gioui.org/shader v1.0.8 // indirect
-	github.com/go-text/typesetting v0.3.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/exp/shiny v0.0.0-20250718183923-645b1fa84792 // indirect
 	golang.org/x/mod v0.26.0 // indirect
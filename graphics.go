@@ -0,0 +1,103 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+
+	"gioui.org/gpu/headless"
+	"gioui.org/op/paint"
+)
+
+// Graphics is an offscreen canvas, drawn with the same API as a Proc:
+// shapes, paths, text, transformations and styles.
+//
+// Unlike the canvas of a Proc, the content of a Graphics persists across
+// frames, until painted over or cleared. Its content is read with Image,
+// and drawn on another canvas with DrawImage.
+type Graphics struct {
+	proc *Proc
+	img  *image.RGBA // content rendered by the last call to Image.
+}
+
+// CreateGraphics returns a new transparent Graphics of w×h pixels, with
+// the default drawing style and the fonts collection of p.
+func (p *Proc) CreateGraphics(w, h int) (*Graphics, error) {
+	if w <= 0 || h <= 0 {
+		return nil, fmt.Errorf("p5: invalid graphics size %dx%d", w, h)
+	}
+
+	g := &Graphics{
+		proc: newProc(w, h),
+		img:  image.NewRGBA(image.Rect(0, 0, w, h)),
+	}
+	g.proc.cfg.fonts = p.cfg.fonts
+	g.proc.cfg.th.Shaper = p.cfg.th.Shaper
+
+	var err error
+	g.proc.head, err = headless.NewWindow(w, h)
+	if err != nil {
+		return nil, fmt.Errorf("p5: could not create headless window: %w", err)
+	}
+	return g, nil
+}
+
+// Background paints the whole graphics with the c color, over its
+// current content.
+// Semi-transparent colors fade the content drawn so far.
+func (g *Graphics) Background(c color.Color) {
+	g.proc.stk.cur().bkg = c
+	if g.proc.stk.mask != nil || c == nil {
+		return
+	}
+	paint.Fill(g.proc.stk.ops, rgba(c))
+}
+
+// Clear discards the content of the graphics, making it transparent.
+//
+// As with Image, transformations and clipping masks are discarded.
+func (g *Graphics) Clear() {
+	g.proc.stk.reset()
+	g.proc.ctx.Ops.Reset()
+	g.proc.pix = nil
+	g.img = image.NewRGBA(g.img.Rect)
+}
+
+// Image returns the content of the graphics.
+//
+// Image ends the current frame of the graphics: as at the end of Draw for
+// a Proc, transformations and clipping masks are discarded, while drawing
+// styles are kept.
+func (g *Graphics) Image() image.Image {
+	g.proc.stk.reset()
+	g.proc.pix = nil
+	img, err := g.proc.snapshot()
+	if err != nil {
+		log.Printf("%+v", err)
+		return g.img
+	}
+
+	// replace the operations drawn so far with their rendering.
+	g.img = img
+	g.proc.ctx.Ops.Reset()
+	src := paint.NewImageOp(img)
+	src.Filter = paint.FilterNearest
+	src.Add(g.proc.ctx.Ops)
+	paint.PaintOp{}.Add(g.proc.ctx.Ops)
+	return img
+}
+
+// Release releases the resources held by the graphics.
+// The graphics must not be used afterwards.
+func (g *Graphics) Release() {
+	if g.proc.head == nil {
+		return
+	}
+	g.proc.head.Release()
+	g.proc.head = nil
+}
@@ -0,0 +1,433 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"image"
+	"image/color"
+
+	"gioui.org/font"
+	"gioui.org/x/stroke"
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// BeginClip starts recording a clipping mask.
+// Shapes drawn until EndClip restrict all subsequent drawing to the area
+// they enclose, until the current context is restored with Pop.
+func (g *Graphics) BeginClip() {
+	g.proc.BeginClip()
+}
+
+// BeginInvertedClip starts recording an inverted clipping mask.
+// Shapes drawn until EndClip restrict all subsequent drawing to the area
+// outside of them, until the current context is restored with Pop.
+func (g *Graphics) BeginInvertedClip() {
+	g.proc.BeginInvertedClip()
+}
+
+// EndClip stops recording the clipping mask and applies it.
+func (g *Graphics) EndClip() {
+	g.proc.EndClip()
+}
+
+// Push saves the current drawing style settings and transformations.
+func (g *Graphics) Push() {
+	g.proc.Push()
+}
+
+// Pop restores the previous drawing style settings and transformations.
+func (g *Graphics) Pop() {
+	g.proc.Pop()
+}
+
+// Rotate rotates the graphical context by angle radians.
+// Positive angles rotate counter-clockwise.
+func (g *Graphics) Rotate(angle float64) {
+	g.proc.Rotate(angle)
+}
+
+// Scale rescales the graphical context by x and y.
+func (g *Graphics) Scale(x, y float64) {
+	g.proc.Scale(x, y)
+}
+
+// Translate applies a translation by x and y.
+func (g *Graphics) Translate(x, y float64) {
+	g.proc.Translate(x, y)
+}
+
+// Shear shears the graphical context by the given x and y angles in radians.
+func (g *Graphics) Shear(x, y float64) {
+	g.proc.Shear(x, y)
+}
+
+// Matrix sets the affine matrix transformation.
+func (g *Graphics) Matrix(a, b, c, d, e, f float64) {
+	g.proc.Matrix(a, b, c, d, e, f)
+}
+
+// BeginPath starts a new path, made of connected lines and curves.
+func (g *Graphics) BeginPath() *Path {
+	return g.proc.BeginPath()
+}
+
+// BeginShape starts a new path, whose vertices are connected according
+// to the provided kind of shape.
+func (g *Graphics) BeginShape(kind ShapeKind) *Path {
+	return g.proc.BeginShape(kind)
+}
+
+// NewPath creates a new path, made of connected lines and curves.
+//
+// Unlike shapes, the path is not drawn when it ends. It is retained to
+// be drawn any number of times, e.g. once per frame, with DrawPath.
+func (g *Graphics) NewPath() *Path {
+	return g.proc.NewPath()
+}
+
+// DrawPath draws the path with the current style and transformation.
+//
+// The outline and stroke of the path are computed once and reused for
+// later calls, as long as neither the path nor the stroke style change.
+func (g *Graphics) DrawPath(path *Path) {
+	g.proc.DrawPath(path)
+}
+
+// ParsePathData parses the SVG path data, such as "M 10 10 L 20 10 Z",
+// and returns the path it describes, in user coordinates.
+//
+// All commands are supported, in their absolute and relative forms:
+// M, L, H, V, C, S, Q, T, A and Z.
+// The returned path is retained, as if created with NewPath.
+func (g *Graphics) ParsePathData(data string) (*Path, error) {
+	return g.proc.ParsePathData(data)
+}
+
+// Stroke sets the color of the strokes.
+func (g *Graphics) Stroke(c color.Color) {
+	g.proc.Stroke(c)
+}
+
+// StrokeWidth sets the size of the strokes.
+func (g *Graphics) StrokeWidth(v float64) {
+	g.proc.StrokeWidth(v)
+}
+
+// StrokeCap sets the style of the ends of strokes.
+//
+// The default style is stroke.RoundCap.
+func (g *Graphics) StrokeCap(cap stroke.StrokeCap) {
+	g.proc.StrokeCap(cap)
+}
+
+// Fill sets the color used to fill shapes.
+func (g *Graphics) Fill(c color.Color) {
+	g.proc.Fill(c)
+}
+
+// RectMode sets how the parameters of Rect and Square are interpreted.
+//
+// The default mode is ModeCorner.
+func (g *Graphics) RectMode(mode ShapeMode) {
+	g.proc.RectMode(mode)
+}
+
+// EllipseMode sets how the parameters of Ellipse, Circle and Arc are
+// interpreted.
+//
+// The default mode is ModeCenter.
+func (g *Graphics) EllipseMode(mode ShapeMode) {
+	g.proc.EllipseMode(mode)
+}
+
+// ImageMode sets how the position of images drawn with DrawImage is
+// interpreted.
+// With ModeCenter or ModeRadius, (x,y) is the center of the image.
+// Otherwise, (x,y) is its upper-left corner.
+//
+// The default mode is ModeCorner.
+func (g *Graphics) ImageMode(mode ShapeMode) {
+	g.proc.ImageMode(mode)
+}
+
+// CurveTightness determines how the curve fits to the Curve vertex points.
+// CurveTightness controls the Catmull-Rom tau tension.
+//
+// The default value is 0.
+func (g *Graphics) CurveTightness(v float64) {
+	g.proc.CurveTightness(v)
+}
+
+// TextSize sets the text size.
+func (g *Graphics) TextSize(size float64) {
+	g.proc.TextSize(size)
+}
+
+// TextFont sets the text font.
+func (g *Graphics) TextFont(fnt font.Font) {
+	g.proc.TextFont(fnt)
+}
+
+// TextStyle sets the weight and slant of the current text font.
+// The typeface of the font is kept, while TextFont replaces it all.
+func (g *Graphics) TextStyle(style FontStyle) {
+	g.proc.TextStyle(style)
+}
+
+// TextColor sets the color used to fill text.
+// A nil color disables filling text.
+//
+// The default color is black.
+func (g *Graphics) TextColor(c color.Color) {
+	g.proc.TextColor(c)
+}
+
+// TextStroke sets the color used to outline text, with the current
+// stroke width.
+// A nil color disables outlining text.
+//
+// By default, text is not outlined.
+func (g *Graphics) TextStroke(c color.Color) {
+	g.proc.TextStroke(c)
+}
+
+// TextAlign sets how text is positioned relative to the point it is
+// drawn at: horizontally with AlignLeft, AlignCenter or AlignRight, and
+// vertically with AlignTop, AlignCenter, AlignBaseline or AlignBottom.
+//
+// The default alignment is AlignLeft and AlignBaseline.
+func (g *Graphics) TextAlign(horizontal, vertical TextAlignment) {
+	g.proc.TextAlign(horizontal, vertical)
+}
+
+// TextLeading sets the spacing between the baselines of lines of text,
+// in the same unit as TextSize.
+// A zero leading selects a spacing proportional to the text size.
+func (g *Graphics) TextLeading(leading float64) {
+	g.proc.TextLeading(leading)
+}
+
+// Text draws txt on the screen at (x,y).
+// Lines of text are positioned according to the current text alignment.
+func (g *Graphics) Text(txt string, x, y float64) {
+	g.proc.Text(txt, x, y)
+}
+
+// TextWidth returns the width of txt, in user coordinates, when drawn
+// with the current text style.
+// The width of multi-line text is the width of its widest line.
+func (g *Graphics) TextWidth(txt string) float64 {
+	return g.proc.TextWidth(txt)
+}
+
+// TextAscent returns the ascent of the current font at the current text
+// size, in user coordinates: the distance from the baseline to the top
+// of a line of text.
+func (g *Graphics) TextAscent() float64 {
+	return g.proc.TextAscent()
+}
+
+// TextDescent returns the descent of the current font at the current text
+// size, in user coordinates: the distance from the baseline to the bottom
+// of a line of text.
+func (g *Graphics) TextDescent() float64 {
+	return g.proc.TextDescent()
+}
+
+// TextBounds returns the bounding box, in user coordinates, of txt when
+// drawn at (x,y) with Text and the current text style and alignment.
+// The current transformation is not taken into account.
+func (g *Graphics) TextBounds(txt string, x, y float64) r2.Box {
+	return g.proc.TextBounds(txt, x, y)
+}
+
+// TextBox draws txt within the box described by the (x,y,w,h) parameters,
+// interpreted according to the current rectangle mode.
+//
+// Lines are broken at explicit line breaks and wrapped at word boundaries
+// to fit the width of the box. Lines overflowing its height are dropped
+// and the last drawn line ends with an ellipsis.
+// Text is positioned in the box according to the current text alignment,
+// where AlignBaseline behaves as AlignTop.
+func (g *Graphics) TextBox(txt string, x, y, w, h float64) {
+	g.proc.TextBox(txt, x, y, w, h)
+}
+
+// TextToPath returns the outlines of the glyphs of txt, as drawn at (x,y)
+// by Text with the current text style and alignment.
+//
+// The returned path is retained, as if created with NewPath.
+// Glyphs without outlines, such as bitmap emojis, and glyphs from system
+// fonts outside of the fonts collection are left out.
+func (g *Graphics) TextToPath(txt string, x, y float64) *Path {
+	return g.proc.TextToPath(txt, x, y)
+}
+
+// TextToPoints returns points evenly spaced along the outlines of the
+// glyphs of txt, as drawn at (x,y) by Text, in user coordinates.
+//
+// The sampleFactor sets the number of points per unit of outline length.
+func (g *Graphics) TextToPoints(txt string, x, y, sampleFactor float64) (xs, ys []float64) {
+	return g.proc.TextToPoints(txt, x, y, sampleFactor)
+}
+
+// TextOnPath draws txt along the path, with the baseline of each glyph
+// following the path and each glyph rotated to the direction of the path
+// at its middle.
+//
+// The offset is the distance along the path, in user coordinates, where
+// the text is anchored according to the current horizontal alignment.
+// The current vertical alignment places the text across the path.
+// Line breaks are drawn as spaces, and glyphs whose middle lies beyond
+// the ends of the path are left out.
+func (g *Graphics) TextOnPath(txt string, path *Path, offset float64) {
+	g.proc.TextOnPath(txt, path, offset)
+}
+
+// DrawImage draws the provided image at (x,y).
+func (g *Graphics) DrawImage(img image.Image, x, y float64) {
+	g.proc.DrawImage(img, x, y)
+}
+
+// Ellipse draws an ellipse at (x,y) with the provided width and height.
+func (g *Graphics) Ellipse(x, y, w, h float64) {
+	g.proc.Ellipse(x, y, w, h)
+}
+
+// Circle draws a circle at (x,y) with a diameter d.
+func (g *Graphics) Circle(x, y, d float64) {
+	g.proc.Circle(x, y, d)
+}
+
+// Arc draws an ellipsoidal arc centered at (x,y), with the provided
+// width and height, and a path from the beg to end radians.
+// Positive angles denote a counter-clockwise path.
+// The mode selects how the arc is closed.
+func (g *Graphics) Arc(x, y, w, h float64, beg, end float64, mode ArcMode) {
+	g.proc.Arc(x, y, w, h, beg, end, mode)
+}
+
+// Line draws a line between (x1,y1) and (x2,y2).
+func (g *Graphics) Line(x1, y1, x2, y2 float64) {
+	g.proc.Line(x1, y1, x2, y2)
+}
+
+// Point draws a point at (x,y).
+//
+// Points are drawn with the stroke color. Their size is the stroke width,
+// and their shape follows the stroke cap: points are disks with round
+// caps, and squares otherwise.
+func (g *Graphics) Point(x, y float64) {
+	g.proc.Point(x, y)
+}
+
+// Points draws a point at each (xs[i],ys[i]) coordinates, as a single shape.
+func (g *Graphics) Points(xs, ys []float64) {
+	g.proc.Points(xs, ys)
+}
+
+// Quad draws a quadrilateral, connecting the 4 points (x1,y1),
+// (x2,y2), (x3,y3) and (x4,y4) together.
+func (g *Graphics) Quad(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
+	g.proc.Quad(x1, y1, x2, y2, x3, y3, x4, y4)
+}
+
+// Rect draws a rectangle at (x,y) with width w and height h.
+//
+// The corners of the rectangle are rounded when radii are provided:
+// a single radius is applied to all corners, otherwise radii are, in
+// order, the radii of the top-left, top-right, bottom-right and
+// bottom-left corners. Missing radii take the value of the previous one.
+func (g *Graphics) Rect(x, y, w, h float64, radii ...float64) {
+	g.proc.Rect(x, y, w, h, radii...)
+}
+
+// Square draws a square at (x,y) with size s.
+//
+// The corners of the square are rounded when radii are provided,
+// as for Rect.
+func (g *Graphics) Square(x, y, s float64, radii ...float64) {
+	g.proc.Square(x, y, s, radii...)
+}
+
+// Triangle draws a triangle, connecting the 3 points (x1,y1), (x2,y2)
+// and (x3,y3) together.
+func (g *Graphics) Triangle(x1, y1, x2, y2, x3, y3 float64) {
+	g.proc.Triangle(x1, y1, x2, y2, x3, y3)
+}
+
+// Bezier draws a cubic Bézier curve from (x1,y1) to (x4,y4) and two control points (x2,y2) and (x3,y3).
+func (g *Graphics) Bezier(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
+	g.proc.Bezier(x1, y1, x2, y2, x3, y3, x4, y4)
+}
+
+// Curve draws a curved line starting at (x2,y2) and ending at (x3,y3).
+// (x1,y1) and (x4,y4) are the control points.
+//
+// Curve is an implementation of Catmull-Rom splines.
+func (g *Graphics) Curve(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
+	g.proc.Curve(x1, y1, x2, y2, x3, y3, x4, y4)
+}
+
+// DrawSVG draws the SVG document in the w×h rectangle at (x,y).
+//
+// As with DrawImage, (x,y) is the center of the rectangle with ModeCenter
+// or ModeRadius image modes, and its corner otherwise.
+// The document is drawn upright and its viewBox is stretched to fill the
+// rectangle. When w or h is not positive, the intrinsic size of the
+// document is used instead.
+//
+// Shapes are drawn with their own fill and stroke styles, under the
+// current transformation.
+func (g *Graphics) DrawSVG(doc *SVG, x, y, w, h float64) {
+	g.proc.DrawSVG(doc, x, y, w, h)
+}
+
+// LoadPixels captures the content of the canvas drawn so far in the
+// current frame and returns it.
+//
+// The returned image holds the pixels read by Get and modified by Set,
+// until the next call to LoadPixels. Changes to the image are drawn on
+// the canvas with UpdatePixels.
+func (g *Graphics) LoadPixels() (*image.RGBA, error) {
+	return g.proc.LoadPixels()
+}
+
+// UpdatePixels draws the pixels loaded with LoadPixels, and modified
+// since, over the canvas.
+// Pixels are drawn as is, regardless of the current transformation.
+func (g *Graphics) UpdatePixels() {
+	g.proc.UpdatePixels()
+}
+
+// Get returns the color of the pixel at (x,y), in pixels from the
+// upper-left corner of the canvas.
+//
+// Get reads the pixels loaded by the last call to LoadPixels, and loads
+// them first if needed. Pixels outside of the canvas are transparent.
+func (g *Graphics) Get(x, y int) color.Color {
+	return g.proc.Get(x, y)
+}
+
+// Set sets the color of the pixel at (x,y), in pixels from the upper-left
+// corner of the canvas.
+//
+// Set modifies the pixels loaded by the last call to LoadPixels, and loads
+// them first if needed. The canvas shows the change after UpdatePixels.
+// Pixels outside of the canvas are ignored.
+func (g *Graphics) Set(x, y int, c color.Color) {
+	g.proc.Set(x, y, c)
+}
+
+// Shade draws over the canvas the colors returned by f for every pixel of
+// the canvas, evaluated at the center of the pixel in user coordinates.
+// A nil color leaves the pixel unchanged.
+//
+// Pixels are drawn as is, regardless of the current transformation.
+// Rows of pixels are evaluated concurrently, across all CPU cores: f
+// must be safe for concurrent use.
+func (g *Graphics) Shade(f func(x, y float64) color.Color) {
+	g.proc.Shade(f)
+}
@@ -0,0 +1,137 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestGraphics(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	var layer *Graphics
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			proc.Background(color.Gray{Y: 220})
+
+			var err error
+			layer, err = proc.CreateGraphics(100, 100)
+			if err != nil {
+				t.Errorf("could not create graphics: %+v", err)
+				return
+			}
+			layer.Background(color.RGBA{B: 255, A: 255})
+			layer.Fill(color.RGBA{R: 255, A: 255})
+			layer.Stroke(nil)
+			layer.Push()
+			layer.Translate(50, 50)
+			layer.Triangle(-40, 30, 0, -40, 40, 30)
+			layer.Pop()
+			layer.TextSize(20)
+			layer.TextColor(color.White)
+			layer.TextAlign(AlignCenter, AlignCenter)
+			layer.Text("p5", 50, 50)
+		},
+		func(proc *Proc) {
+			if layer == nil {
+				return
+			}
+			// content drawn in previous frames is kept.
+			layer.Fill(color.RGBA{G: 255, A: 255})
+			layer.Circle(85, 85, 20)
+
+			img := layer.Image()
+			proc.DrawImage(img, 0, 0)
+			proc.ImageMode(ModeCenter)
+			proc.DrawImage(img, 150, 150)
+		},
+		"testdata/graphics.png",
+		imgDelta,
+	)
+	defer func() {
+		if layer != nil {
+			layer.Release()
+		}
+	}()
+
+	proc.Run(t)
+}
+
+func TestGraphicsImage(t *testing.T) {
+	g, err := newProc(100, 100).CreateGraphics(20, 10)
+	if err != nil {
+		t.Fatalf("could not create graphics: %+v", err)
+	}
+	defer g.Release()
+
+	var (
+		red  = color.RGBA{R: 255, A: 255}
+		blue = color.RGBA{B: 255, A: 255}
+	)
+	at := func(img image.Image, x, y int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+
+	img := g.Image()
+	if got, want := img.Bounds(), image.Rect(0, 0, 20, 10); got != want {
+		t.Fatalf("invalid bounds: got=%v, want=%v", got, want)
+	}
+	if got := at(img, 5, 5); got != (color.RGBA{}) {
+		t.Fatalf("graphics not transparent: got=%v", got)
+	}
+
+	g.Background(red)
+	g.Translate(10, 0)
+	g.Fill(blue)
+	g.Stroke(nil)
+	g.Rect(0, 0, 10, 10)
+	img = g.Image()
+	if got := at(img, 5, 5); got != red {
+		t.Fatalf("invalid background: got=%v, want=%v", got, red)
+	}
+	if got := at(img, 15, 5); got != blue {
+		t.Fatalf("invalid rect: got=%v, want=%v", got, blue)
+	}
+
+	// content persists, while transformations are discarded.
+	g.Rect(0, 0, 5, 5)
+	img = g.Image()
+	for _, tc := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{2, 2, blue},
+		{7, 7, red},
+		{15, 5, blue},
+	} {
+		if got := at(img, tc.x, tc.y); got != tc.want {
+			t.Fatalf("invalid pixel at (%d,%d): got=%v, want=%v", tc.x, tc.y, got, tc.want)
+		}
+	}
+
+	g.Clear()
+	if got := at(g.Image(), 15, 5); got != (color.RGBA{}) {
+		t.Fatalf("graphics not cleared: got=%v", got)
+	}
+}
+
+func TestCreateGraphicsInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		w, h int
+	}{
+		{0, 10},
+		{10, 0},
+		{-1, 10},
+	} {
+		_, err := newProc(100, 100).CreateGraphics(tc.w, tc.h)
+		if err == nil {
+			t.Fatalf("expected an error for %dx%d", tc.w, tc.h)
+		}
+	}
+}
@@ -6,46 +6,374 @@ package p5
 
 import (
 	"fmt"
+	"math"
 
 	"gioui.org/f32"
 	"gioui.org/op"
 	"gioui.org/op/clip"
-	"gioui.org/op/paint"
 	"gioui.org/x/stroke"
 	bstroke "github.com/andybalholm/stroke"
+	"gonum.org/v1/gonum/spatial/r2"
 )
 
+// BeginPath starts a new path, made of connected lines and curves.
 func (p *Proc) BeginPath() *Path {
-	pp := &Path{proc: p}
+	return p.BeginShape(ShapePolygon)
+}
+
+// BeginShape starts a new path, whose vertices are connected according
+// to the provided kind of shape.
+func (p *Proc) BeginShape(kind ShapeKind) *Path {
+	pp := &Path{
+		proc: p,
+		kind: kind,
+		u2sX: p.cfg.u2sX,
+		u2sY: p.cfg.u2sY,
+		s2uX: p.cfg.s2uX,
+		s2uY: p.cfg.s2uY,
+		tau:  p.stk.cur().tau,
+	}
 	return pp
 }
 
+// ShapeKind describes how the vertices of a path are connected.
+type ShapeKind uint8
+
+const (
+	// ShapePolygon connects all vertices, lines and curves of the path.
+	ShapePolygon ShapeKind = iota
+
+	// ShapePoints draws each vertex as a point.
+	ShapePoints
+
+	// ShapeLines draws a line between each pair of vertices.
+	ShapeLines
+
+	// ShapeTriangles draws a triangle for each group of 3 vertices.
+	ShapeTriangles
+
+	// ShapeTriangleStrip draws a triangle for each vertex and the
+	// 2 vertices preceding it.
+	ShapeTriangleStrip
+
+	// ShapeTriangleFan draws a triangle for each vertex, the vertex
+	// preceding it and the first vertex of the path.
+	ShapeTriangleFan
+
+	// ShapeQuads draws a quadrilateral for each group of 4 vertices.
+	ShapeQuads
+
+	// ShapeQuadStrip draws a quadrilateral for each pair of vertices
+	// and the 2 vertices preceding them.
+	ShapeQuadStrip
+)
+
+// Path is a shape made of vertices, lines and curves.
+//
+// Curves and Close are only honored by ShapePolygon paths.
+// The geometry of a path may still be queried once it has been drawn.
 type Path struct {
 	proc *Proc
+	u2sX func(v float64) float64 // translate from user- to system coords
+	u2sY func(v float64) float64 // translate from user- to system coords
+	s2uX func(v float64) float64 // translate from system- to user coords
+	s2uY func(v float64) float64 // translate from system- to user coords
+	kind ShapeKind
+	rule FillRule
 	segs []segment
+	vtxs []f32.Point // vertices of non-polygon shapes.
 	vtx  int
+
+	conts []segment // closed contours of polygon shapes, such as holes.
+	cont  bool      // whether a contour is being recorded.
+	cvtx  int       // number of vertices of the contour being recorded.
+
+	retained bool       // whether the path is drawn with DrawPath.
+	cache    *pathCache // clip operations of retained paths.
+
+	pos  cursor      // position in the path.
+	cpos cursor      // position in the contour being recorded.
+	crs  []f32.Point // pending Catmull-Rom vertices.
+	tau  float32     // Catmull-Rom tension, when the path was started.
+}
+
+// cursor tracks the drawing position along a path.
+type cursor struct {
+	pen f32.Point // current position.
+	beg f32.Point // start of the current sub-path.
+}
+
+// FillRule describes how the inside of a path is determined.
+type FillRule uint8
+
+const (
+	// NonZero fills the areas a path winds around a non-zero number
+	// of times, taking the direction of its contours into account.
+	NonZero FillRule = iota
+
+	// EvenOdd fills the areas enclosed by an odd number of contours,
+	// regardless of their direction.
+	//
+	// Curves of EvenOdd paths are approximated with line segments.
+	EvenOdd
+)
+
+// inside returns whether the fill rule fills areas with the w winding number.
+func (rule FillRule) inside(w int) bool {
+	switch rule {
+	case EvenOdd:
+		return w%2 != 0
+	default:
+		return w != 0
+	}
 }
 
 func (p *Path) pt(x, y float64) f32.Point {
-	return p.proc.pt(x, y)
+	return f32.Point{
+		X: float32(p.u2sX(x)),
+		Y: float32(p.u2sY(y)),
+	}
+}
+
+// mirrored returns whether the conversion from user to system
+// coordinates of the path reverses the orientation of shapes.
+func (p *Path) mirrored() bool {
+	var (
+		sx = p.u2sX(1) - p.u2sX(0)
+		sy = p.u2sY(1) - p.u2sY(0)
+	)
+	return sx*sy < 0
+}
+
+func (p *Path) inc() {
+	if p.cont {
+		p.cvtx++
+		return
+	}
+	p.vtx++
 }
 
-func (p *Path) inc() { p.vtx++ }
+func (p *Path) first() bool {
+	if p.cont {
+		return p.cvtx == 0
+	}
+	return p.vtx == 0
+}
 
+func (p *Path) cursor() *cursor {
+	if p.cont {
+		return &p.cpos
+	}
+	return &p.pos
+}
+
+// add appends the segment to the path, or to the contour being recorded,
+// and ends any pending Catmull-Rom spline.
+func (p *Path) add(seg segment) {
+	p.crs = p.crs[:0]
+	p.append(seg)
+}
+
+func (p *Path) append(seg segment) {
+	p.cache = nil
+	cur := p.cursor()
+	switch seg.op {
+	case segOpMoveTo:
+		cur.beg = seg.args[0]
+		cur.pen = cur.beg
+	case segOpClose:
+		cur.pen = cur.beg
+	case segOpArcTo:
+		// the end of an arc is updated by its caller.
+	default:
+		cur.pen = seg.end()
+	}
+	if p.cont {
+		p.conts = append(p.conts, seg)
+		return
+	}
+	p.segs = append(p.segs, seg)
+}
+
+// vertex connects the current position to pt, in system coordinates.
+func (p *Path) vertex(pt f32.Point) segment {
+	if p.first() {
+		return opMoveTo(pt)
+	}
+	return opLineTo(pt)
+}
+
+// FillRule sets the rule used to fill the path.
+//
+// The default rule is NonZero.
+func (p *Path) FillRule(rule FillRule) {
+	p.rule = rule
+	p.cache = nil
+}
+
+// Vertex adds the (x,y) vertex to the path.
 func (p *Path) Vertex(x, y float64) {
 	defer p.inc()
-	if p.vtx == 0 {
-		p.segs = append(p.segs, opMoveTo(p.pt(x, y)))
+	if p.kind != ShapePolygon {
+		p.vtxs = append(p.vtxs, p.pt(x, y))
+		p.cache = nil
+		return
+	}
+	p.add(p.vertex(p.pt(x, y)))
+}
+
+// moveTo starts a new sub-path at (x,y).
+func (p *Path) moveTo(x, y float64) {
+	defer p.inc()
+	p.add(opMoveTo(p.pt(x, y)))
+}
+
+// CurveVertex adds the (x,y) vertex to a Catmull-Rom spline.
+//
+// The spline passes through all the consecutive vertices added with
+// CurveVertex, except the first and last ones, which only guide the
+// direction of the curve at its ends.
+// The fit of the spline is controlled with CurveTightness, as set when
+// the path was started.
+func (p *Path) CurveVertex(x, y float64) {
+	p.crs = append(p.crs, p.pt(x, y))
+	n := len(p.crs)
+	if n < 4 {
+		return
+	}
+
+	var (
+		cr       = p.crs[n-4:]
+		cp0, cp1 = catmullRom(cr[0], cr[1], cr[2], cr[3], p.tau)
+	)
+	if n == 4 {
+		p.append(p.vertex(cr[1]))
+		p.inc()
+	}
+	p.append(opCubeTo(cp0, cp1, cr[2]))
+	p.inc()
+}
+
+// Arc adds an elliptical arc, centered at (x,y) with the rx and ry radii,
+// from the beg angle to the end angle, in radians.
+// A line joins the current position to the start of the arc.
+//
+// Angles increase from the x-axis towards the y-axis.
+func (p *Path) Arc(x, y, rx, ry, beg, end float64) {
+	defer p.inc()
+	sin, cos := math.Sincos(beg)
+	p.add(p.vertex(p.pt(x+rx*cos, y+ry*sin)))
+	p.arc(x, y, rx, ry, 0, end-beg)
+
+	sin, cos = math.Sincos(end)
+	p.cursor().pen = p.pt(x+rx*cos, y+ry*sin)
+}
+
+// ArcTo adds an elliptical arc from the current position to the (x,y)
+// point, following the SVG conventions.
+//
+// The ellipse has the rx and ry radii and is rotated by rot radians.
+// Among the 4 candidate arcs, large selects one spanning more than 180
+// degrees and sweep selects one drawn with increasing angles.
+// The radii are scaled up when they are too small to join both points.
+func (p *Path) ArcTo(rx, ry, rot float64, large, sweep bool, x, y float64) {
+	defer p.inc()
+	if p.first() {
+		p.add(opMoveTo(p.pt(x, y)))
 		return
 	}
-	p.segs = append(p.segs, opLineTo(p.pt(x, y)))
+
+	var (
+		pen    = p.cursor().pen
+		x1, y1 = p.s2uX(float64(pen.X)), p.s2uY(float64(pen.Y))
+	)
+	if x1 == x && y1 == y {
+		return
+	}
+	rx = math.Abs(rx)
+	ry = math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		p.add(opLineTo(p.pt(x, y)))
+		return
+	}
+
+	// convert from endpoint to center parametrization, according to:
+	//  https://www.w3.org/TR/SVG11/implnote.html#ArcConversionEndpointToCenter
+	var (
+		sin, cos = math.Sincos(rot)
+		dx, dy   = 0.5 * (x1 - x), 0.5 * (y1 - y)
+		x1p      = +cos*dx + sin*dy
+		y1p      = -sin*dx + cos*dy
+	)
+	if l := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); l > 1 {
+		l = math.Sqrt(l)
+		rx *= l
+		ry *= l
+	}
+
+	var (
+		num  = rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+		den  = rx*rx*y1p*y1p + ry*ry*x1p*x1p
+		coef = math.Sqrt(math.Max(0, num/den))
+	)
+	if large == sweep {
+		coef = -coef
+	}
+
+	var (
+		cxp = +coef * rx * y1p / ry
+		cyp = -coef * ry * x1p / rx
+		cx  = cos*cxp - sin*cyp + 0.5*(x1+x)
+		cy  = sin*cxp + cos*cyp + 0.5*(y1+y)
+
+		beg   = math.Atan2((y1p-cyp)/ry, (x1p-cxp)/rx)
+		end   = math.Atan2((-y1p-cyp)/ry, (-x1p-cxp)/rx)
+		angle = end - beg
+	)
+	switch {
+	case sweep && angle < 0:
+		angle += 2 * math.Pi
+	case !sweep && angle > 0:
+		angle -= 2 * math.Pi
+	}
+
+	p.arc(cx, cy, rx, ry, rot, angle)
+	p.cursor().pen = p.pt(x, y)
+}
+
+// arc adds an arc of the ellipse centered at (x,y), with the rx and ry
+// radii rotated by rot, sweeping angle radians from the current position.
+// Callers must update the current position to the end of the arc.
+func (p *Path) arc(x, y, rx, ry, rot, angle float64) {
+	var (
+		sin, cos = math.Sincos(rot)
+		c        = p.pt(x, y)
+		// conjugate semi-axes of the ellipse, in system coordinates.
+		u = p.pt(x+rx*cos, y+rx*sin).Sub(c)
+		v = p.pt(x-ry*sin, y+ry*cos).Sub(c)
+
+		// principal axes of the ellipse, from the eigen-decomposition
+		// of the symmetric [[a b] [b d]] matrix.
+		a = float64(u.X*u.X + v.X*v.X)
+		b = float64(u.X*u.Y + v.X*v.Y)
+		d = float64(u.Y*u.Y + v.Y*v.Y)
+
+		f     = math.Sqrt(2 * math.Hypot(0.5*(a-d), b)) // focal distance.
+		theta = 0.5 * math.Atan2(2*b, a-d)
+		df    = f32.Pt(float32(f*math.Cos(theta)), float32(f*math.Sin(theta)))
+	)
+	if p.mirrored() {
+		angle = -angle
+	}
+
+	p.add(opArcTo(c.Add(df), c.Sub(df), float32(angle)))
 }
 
 // Cube draws a cubic Bézier curve from the current position
 // to the (x3,y3) point, with the (x1,y1) and (x2,y2) control points.
 func (p *Path) Cube(x1, y1, x2, y2, x3, y3 float64) {
 	defer p.inc()
-	p.segs = append(p.segs, opCubeTo(
+	p.add(opCubeTo(
 		p.pt(x1, y1),
 		p.pt(x2, y2),
 		p.pt(x3, y3),
@@ -56,7 +384,7 @@ func (p *Path) Cube(x1, y1, x2, y2, x3, y3 float64) {
 // the (x2,y2) point, with the (x1,y1) control point.
 func (p *Path) Quad(x1, y1, x2, y2 float64) {
 	defer p.inc()
-	p.segs = append(p.segs, opQuadTo(
+	p.add(opQuadTo(
 		p.pt(x1, y1),
 		p.pt(x2, y2),
 	))
@@ -64,39 +392,267 @@ func (p *Path) Quad(x1, y1, x2, y2 float64) {
 
 // Close closes the current path.
 func (p *Path) Close() {
-	p.segs = append(p.segs, segment{op: segOpClose})
+	p.add(segment{op: segOpClose})
+}
+
+// BeginContour starts a new contour, such as a hole, inside the path.
+//
+// The vertices and curves added until EndContour make up a separate
+// closed outline, independent of the rest of the path.
+// With the NonZero fill rule, holes must be drawn in the direction
+// opposite to the outline enclosing them.
+func (p *Path) BeginContour() {
+	p.crs = p.crs[:0]
+	p.cont = true
+	p.cvtx = 0
 }
 
+// EndContour closes the current contour.
+func (p *Path) EndContour() {
+	p.crs = p.crs[:0]
+	if p.cont && p.cvtx > 0 {
+		p.add(segment{op: segOpClose})
+	}
+	p.cont = false
+}
+
+// End draws the path.
+//
+// Paths created with NewPath are not drawn: End only completes them.
 func (p *Path) End() {
-	if p.proc.doFill() {
-		stk := op.TransformOp{}.Push(p.proc.ctx.Ops)
-		paint.FillShape(
-			p.proc.ctx.Ops,
-			rgba(p.proc.stk.cur().fill),
-			p.outline(),
-		)
-		stk.Pop()
-	}
-
-	if p.proc.doStroke() {
-		stk := op.TransformOp{}.Push(p.proc.ctx.Ops)
-		paint.FillShape(
-			p.proc.ctx.Ops,
-			rgba(p.proc.stk.cur().stroke.color),
-			p.stroke(),
-		)
-		stk.Pop()
+	if p.retained {
+		p.EndContour()
+		return
 	}
 
+	switch p.kind {
+	case ShapePoints:
+		p.proc.points(p.vtxs)
+	case ShapeLines:
+		p.proc.strokeShape(p.shape())
+	default:
+		p.proc.fillShape(p.fill())
+		p.proc.strokeShape(p.shape())
+	}
 	p.proc = nil
 }
 
-func (p *Path) outline() clip.Op {
-	return segments(p.segs).outline(p.proc.ctx.Ops)
+// fill returns the segments enclosing the area filled by the path,
+// oriented for the non-zero rule.
+func (p *Path) fill() segments {
+	segs := p.shape()
+	if p.rule == EvenOdd {
+		segs = segs.evenOdd()
+	}
+	return segs
 }
 
-func (p *Path) stroke() clip.Op {
-	return segments(p.segs).stroke(p.proc.ctx.Ops, p.proc.stk.cur().stroke)
+// NewPath creates a new path, made of connected lines and curves.
+//
+// Unlike BeginPath, the path is not drawn when it ends. It is retained to
+// be drawn any number of times, e.g. once per frame, with DrawPath.
+func (p *Proc) NewPath() *Path {
+	pp := p.BeginPath()
+	pp.retained = true
+	return pp
+}
+
+// DrawPath draws the path with the current style and transformation.
+//
+// The outline and stroke of the path are computed once and reused for
+// later calls, as long as neither the path nor the stroke style change.
+// The path must have been created for a canvas with the same geometry.
+func (p *Proc) DrawPath(path *Path) {
+	switch {
+	case p.stk.mask != nil:
+		if path.filled() {
+			p.fillShape(path.fill())
+		}
+		return
+	case !p.doShape():
+		return
+	case path.kind == ShapePoints:
+		p.points(path.vtxs)
+		return
+	}
+
+	sty := p.stk.cur().stroke
+	if path.cache == nil || !path.cache.sameStroke(sty) {
+		path.cache = &pathCache{sty: sty}
+	}
+	cache := path.cache
+
+	if path.filled() && p.doFill() {
+		if !cache.filled {
+			cache.fill = path.fill().outline(&cache.ops)
+			cache.filled = true
+		}
+		p.paintShape(p.stk.cur().fill, cache.fill)
+	}
+	if p.doStroke() {
+		if !cache.stroked {
+			cache.stroke = path.shape().stroke(&cache.ops, sty)
+			cache.stroked = true
+		}
+		p.paintShape(sty.color, cache.stroke)
+	}
+}
+
+// pathCache holds the clip operations of a retained path.
+// They are recorded in their own list of operations, so they outlive the
+// frame they were first drawn in.
+type pathCache struct {
+	ops op.Ops
+	sty strokeStyle // stroke style of the cached stroke.
+
+	fill    clip.Op
+	filled  bool
+	stroke  clip.Op
+	stroked bool
+}
+
+// sameStroke returns whether the cached stroke matches the sty style.
+// The color of the stroke is irrelevant, as it is applied when painting.
+func (c *pathCache) sameStroke(sty strokeStyle) bool {
+	var (
+		a = c.sty.style
+		b = sty.style
+	)
+	if a.width != b.width || a.cap != b.cap || a.join != b.join ||
+		a.dashes.Phase != b.dashes.Phase ||
+		len(a.dashes.Dashes) != len(b.dashes.Dashes) {
+		return false
+	}
+	for i, v := range a.dashes.Dashes {
+		if v != b.dashes.Dashes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// shape returns the segments making up the path, in system coordinates.
+// Points have no extent and yield no segments.
+func (p *Path) shape() segments {
+	var (
+		segs segments
+		vs   = p.vtxs
+	)
+
+	switch p.kind {
+	case ShapePolygon:
+		segs = append(segs, p.segs...)
+		segs = append(segs, p.conts...)
+	case ShapeLines:
+		for i := 1; i < len(vs); i += 2 {
+			segs = append(segs, opMoveTo(vs[i-1]), opLineTo(vs[i]))
+		}
+	case ShapeTriangles:
+		for i := 2; i < len(vs); i += 3 {
+			segs = append(segs, polygon(vs[i-2], vs[i-1], vs[i])...)
+		}
+	case ShapeTriangleStrip:
+		for i := 2; i < len(vs); i++ {
+			segs = append(segs, polygon(vs[i-2], vs[i-1], vs[i])...)
+		}
+	case ShapeTriangleFan:
+		for i := 2; i < len(vs); i++ {
+			segs = append(segs, polygon(vs[0], vs[i-1], vs[i])...)
+		}
+	case ShapeQuads:
+		for i := 3; i < len(vs); i += 4 {
+			segs = append(segs, polygon(vs[i-3], vs[i-2], vs[i-1], vs[i])...)
+		}
+	case ShapeQuadStrip:
+		for i := 3; i < len(vs); i += 2 {
+			segs = append(segs, polygon(vs[i-3], vs[i-2], vs[i], vs[i-1])...)
+		}
+	}
+	return segs
+}
+
+// Sample returns n points evenly spaced along the path by arc length,
+// in user coordinates.
+//
+// The first and last points are the start and end of the path.
+// Closed sub-paths include the line closing them; the gaps between
+// sub-paths do not count towards the arc length.
+func (p *Path) Sample(n int) (xs, ys []float64) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	var (
+		lines = p.polylines()
+		total float64
+	)
+	for _, ps := range lines {
+		for i := 1; i < len(ps); i++ {
+			total += dist(ps[i-1], ps[i])
+		}
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	xs = make([]float64, 0, n)
+	ys = make([]float64, 0, n)
+	add := func(p f32.Point) {
+		xs = append(xs, float64(p.X))
+		ys = append(ys, float64(p.Y))
+	}
+
+	var (
+		step = 0.0
+		s    = 0.0 // arc length of the next sample.
+		pos  = 0.0 // arc length at the start of the current line.
+	)
+	if n > 1 {
+		step = total / float64(n-1)
+	}
+	for _, ps := range lines {
+		for i := 1; i < len(ps) && len(xs) < n; i++ {
+			d := dist(ps[i-1], ps[i])
+			for len(xs) < n && s <= pos+d {
+				t := float32(0)
+				if d > 0 {
+					t = float32((s - pos) / d)
+				}
+				add(ps[i-1].Add(ps[i].Sub(ps[i-1]).Mul(t)))
+				s = step * float64(len(xs))
+			}
+			pos += d
+		}
+	}
+
+	// rounding errors may leave out the last samples.
+	last := lines[len(lines)-1]
+	for len(xs) < n {
+		add(last[len(last)-1])
+	}
+	return xs, ys
+}
+
+// polygon returns the closed polygon connecting the provided points,
+// oriented so overlapping polygons do not cancel each other out under
+// the non-zero filling rule.
+func polygon(ps ...f32.Point) segments {
+	if polyArea(ps) < 0 {
+		for i, j := 0, len(ps)-1; i < j; i, j = i+1, j-1 {
+			ps[i], ps[j] = ps[j], ps[i]
+		}
+	}
+
+	segs := make(segments, 0, len(ps)+1)
+	for i, p := range ps {
+		switch i {
+		case 0:
+			segs = append(segs, opMoveTo(p))
+		default:
+			segs = append(segs, opLineTo(p))
+		}
+	}
+	return append(segs, segment{op: segOpClose})
 }
 
 type segment struct {
@@ -154,12 +710,30 @@ func opArcTo(f1, f2 f32.Point, angle float32) segment {
 	s := segment{
 		op: segOpArcTo,
 	}
+	if f1 != f2 {
+		// Gio derives the orientation of the ellipse from the distance
+		// between its foci, computed both in single and double precision.
+		// Snap the foci on a grid where both computations agree, lest
+		// rounding errors yield an invalid (NaN) orientation.
+		f1 = snap(f1)
+		f2 = snap(f2)
+	}
 	s.args[0] = f1
 	s.args[1] = f2
 	s.args[2].X = angle
 	return s
 }
 
+// snap rounds p to the nearest multiple of 1/256, so coordinates and
+// their differences are exactly represented as float32 values.
+func snap(p f32.Point) f32.Point {
+	const grid = 256
+	return f32.Point{
+		X: float32(math.Round(float64(p.X)*grid) / grid),
+		Y: float32(math.Round(float64(p.Y)*grid) / grid),
+	}
+}
+
 type segments []segment
 
 func (segs segments) outline(ops *op.Ops) clip.Op {
@@ -177,7 +751,15 @@ func (segs segments) outline(ops *op.Ops) clip.Op {
 				f2    = seg.args[1]
 				angle = seg.args[2].X
 			)
-			path.ArcTo(f1, f2, angle)
+			if f1.X == f2.X || f1.Y == f2.Y {
+				path.ArcTo(f1, f2, angle)
+				continue
+			}
+			// Gio ignores the sign of the slope of the major axis of
+			// tilted ellipses: draw them as cubic Bézier curves.
+			for _, arc := range arcTo(path.Pos(), f1, f2, angle) {
+				path.CubeTo(f32.Point(arc.CP1), f32.Point(arc.CP2), f32.Point(arc.End))
+			}
 		case segOpQuadTo:
 			var (
 				ctl = seg.args[0]
@@ -227,6 +809,7 @@ func (segs segments) stroke(ops *op.Ops, sty strokeStyle) clip.Op {
 		case segOpMoveTo:
 			add(stroke.MoveTo(seg.args[0]))
 			pen = seg.args[0]
+			beg = pen
 		case segOpLineTo:
 			add(stroke.LineTo(seg.args[0]))
 			pen = seg.args[0]
@@ -265,3 +848,259 @@ func xStroke(bs []bstroke.Segment) []stroke.Segment {
 	}
 	return vs
 }
+
+// transform returns the segments mapped through the affine transformation m.
+// Elliptical arcs are converted to cubic Bézier curves, as their foci are
+// not preserved by non-uniform transformations.
+func (segs segments) transform(m f32.Affine2D) segments {
+	var (
+		o   = make(segments, 0, len(segs))
+		pen f32.Point
+	)
+	for _, seg := range segs {
+		switch seg.op {
+		case segOpMoveTo:
+			o = append(o, opMoveTo(m.Transform(seg.args[0])))
+			pen = seg.args[0]
+		case segOpLineTo:
+			o = append(o, opLineTo(m.Transform(seg.args[0])))
+			pen = seg.args[0]
+		case segOpQuadTo:
+			o = append(o, opQuadTo(
+				m.Transform(seg.args[0]),
+				m.Transform(seg.args[1]),
+			))
+			pen = seg.args[1]
+		case segOpCubeTo:
+			o = append(o, opCubeTo(
+				m.Transform(seg.args[0]),
+				m.Transform(seg.args[1]),
+				m.Transform(seg.args[2]),
+			))
+			pen = seg.args[2]
+		case segOpArcTo:
+			for _, arc := range arcTo(pen, seg.args[0], seg.args[1], seg.args[2].X) {
+				o = append(o, opCubeTo(
+					m.Transform(f32.Point(arc.CP1)),
+					m.Transform(f32.Point(arc.CP2)),
+					m.Transform(f32.Point(arc.End)),
+				))
+				pen = f32.Point(arc.End)
+			}
+		case segOpClose:
+			o = append(o, seg)
+		default:
+			panic(fmt.Errorf("p5: unknown path component %d", seg.op))
+		}
+	}
+	return o
+}
+
+// apply returns the segments with all their points mapped through f.
+// Arcs are not supported: segments must first be transformed.
+func (segs segments) apply(f func(f32.Point) f32.Point) segments {
+	o := make(segments, len(segs))
+	for i, seg := range segs {
+		switch seg.op {
+		case segOpMoveTo, segOpLineTo:
+			seg.args[0] = f(seg.args[0])
+		case segOpQuadTo:
+			seg.args[0] = f(seg.args[0])
+			seg.args[1] = f(seg.args[1])
+		case segOpCubeTo:
+			seg.args[0] = f(seg.args[0])
+			seg.args[1] = f(seg.args[1])
+			seg.args[2] = f(seg.args[2])
+		case segOpClose:
+		default:
+			panic(fmt.Errorf("p5: unknown path component %d", seg.op))
+		}
+		o[i] = seg
+	}
+	return o
+}
+
+// contours splits the segments into sub-paths, each starting with a move-to.
+func (segs segments) contours() []segments {
+	var cs []segments
+	for i, seg := range segs {
+		if seg.op == segOpMoveTo || i == 0 {
+			cs = append(cs, nil)
+		}
+		cs[len(cs)-1] = append(cs[len(cs)-1], seg)
+	}
+	return cs
+}
+
+// end returns the point where the segment ends.
+func (seg segment) end() f32.Point {
+	switch seg.op {
+	case segOpQuadTo:
+		return seg.args[1]
+	case segOpCubeTo:
+		return seg.args[2]
+	default:
+		return seg.args[0]
+	}
+}
+
+// area returns the signed area enclosed by the segments.
+// Curves are approximated with line segments.
+// Arcs are not supported: segments must first be transformed.
+func (segs segments) area() float32 {
+	var area float32
+	for _, c := range segs.contours() {
+		area += polyArea(c.polyline(0))
+	}
+	return area
+}
+
+// polylines returns the flattened contours of the segments, as sequences
+// of vertices. Closed contours end with their starting vertex.
+// Curves are approximated as described for polyline.
+// Arcs are not supported: segments must first be transformed.
+func (segs segments) polylines(tol float32) [][]f32.Point {
+	var (
+		cs = segs.contours()
+		o  = make([][]f32.Point, 0, len(cs))
+	)
+	for _, c := range cs {
+		ps := c.polyline(tol)
+		if len(ps) == 0 {
+			continue
+		}
+		if c[len(c)-1].op == segOpClose {
+			ps = append(ps, ps[0])
+		}
+		o = append(o, ps)
+	}
+	return o
+}
+
+// polyline returns the vertices of a contour, approximating curves
+// with line segments within the tol distance.
+// A null tolerance approximates each curve with 16 line segments.
+// Arcs are not supported: segments must first be transformed.
+func (segs segments) polyline(tol float32) []f32.Point {
+	var (
+		ps  = make([]f32.Point, 0, len(segs))
+		pen f32.Point
+	)
+	for _, seg := range segs {
+		switch seg.op {
+		case segOpMoveTo, segOpLineTo:
+			ps = append(ps, seg.args[0])
+		case segOpQuadTo:
+			n := curveSteps(tol, pen, seg.args[0], seg.args[1])
+			for i := 1; i <= n; i++ {
+				t := float32(i) / float32(n)
+				ps = append(ps, quadAt(pen, seg.args[0], seg.args[1], t))
+			}
+		case segOpCubeTo:
+			n := curveSteps(tol, pen, seg.args[0], seg.args[1], seg.args[2])
+			for i := 1; i <= n; i++ {
+				t := float32(i) / float32(n)
+				ps = append(ps, cubeAt(pen, seg.args[0], seg.args[1], seg.args[2], t))
+			}
+		case segOpClose:
+			continue
+		default:
+			panic(fmt.Errorf("p5: unknown polyline-path component %d", seg.op))
+		}
+		pen = seg.end()
+	}
+	return ps
+}
+
+// curveSteps returns the number of line segments approximating the Bézier
+// curve with the ps control points within the tol distance, according to
+// Wang's formula.
+// A null tolerance yields 16 line segments.
+func curveSteps(tol float32, ps ...f32.Point) int {
+	if tol <= 0 {
+		return 16
+	}
+	var m float64
+	for i := 2; i < len(ps); i++ {
+		d := ps[i-2].Sub(ps[i-1].Mul(2)).Add(ps[i])
+		m = math.Max(m, math.Hypot(float64(d.X), float64(d.Y)))
+	}
+	deg := float64(len(ps) - 1)
+	n := math.Ceil(math.Sqrt(deg * (deg - 1) / 8 * m / float64(tol)))
+	return int(math.Max(1, n))
+}
+
+// evenOdd returns the outline of the area enclosed by the segments under
+// the even-odd rule, oriented for the non-zero rule.
+//
+// Curves are approximated with line segments, and contours are cut where
+// they cross each other or themselves.
+func (segs segments) evenOdd() segments {
+	es := segs.edges()
+	return regionOutline(es, func(pt r2.Vec) bool {
+		return EvenOdd.inside(windingEdges(es, pt))
+	})
+}
+
+func dist(p, q f32.Point) float64 {
+	return math.Hypot(float64(q.X-p.X), float64(q.Y-p.Y))
+}
+
+// polyArea returns the signed area of the closed polygon ps.
+func polyArea(ps []f32.Point) float32 {
+	var area float32
+	for i, p := range ps {
+		q := ps[(i+1)%len(ps)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	return 0.5 * area
+}
+
+// reverse returns the segments traversed in the opposite direction.
+// Arcs are not supported: segments must first be transformed.
+func (segs segments) reverse() segments {
+	var o segments
+	for _, c := range segs.contours() {
+		if len(c) == 0 || c[0].op != segOpMoveTo {
+			continue
+		}
+		closed := c[len(c)-1].op == segOpClose
+		if closed {
+			c = c[:len(c)-1]
+		}
+		o = append(o, opMoveTo(c[len(c)-1].end()))
+		for i := len(c) - 1; i > 0; i-- {
+			var (
+				seg = c[i]
+				beg = c[i-1].end()
+			)
+			switch seg.op {
+			case segOpLineTo:
+				o = append(o, opLineTo(beg))
+			case segOpQuadTo:
+				o = append(o, opQuadTo(seg.args[0], beg))
+			case segOpCubeTo:
+				o = append(o, opCubeTo(seg.args[1], seg.args[0], beg))
+			default:
+				panic(fmt.Errorf("p5: unknown reversible path component %d", seg.op))
+			}
+		}
+		if closed {
+			o = append(o, segment{op: segOpClose})
+		}
+	}
+	return o
+}
+
+func quadAt(p0, p1, p2 f32.Point, t float32) f32.Point {
+	u := 1 - t
+	return p0.Mul(u * u).Add(p1.Mul(2 * u * t)).Add(p2.Mul(t * t))
+}
+
+func cubeAt(p0, p1, p2, p3 f32.Point, t float32) f32.Point {
+	u := 1 - t
+	return p0.Mul(u * u * u).
+		Add(p1.Mul(3 * u * u * t)).
+		Add(p2.Mul(3 * u * t * t)).
+		Add(p3.Mul(t * t * t))
+}
@@ -7,7 +7,10 @@ package p5
 import (
 	"fmt"
 	"image/color"
+	"math"
 	"testing"
+
+	"gioui.org/f32"
 )
 
 func TestPathVertex(t *testing.T) {
@@ -104,3 +107,390 @@ func TestDraw_Framecount(t *testing.T) {
 	)
 	proc.Run(t)
 }
+
+func TestPathShapeKinds(t *testing.T) {
+	var (
+		// vertices of a 3x1 grid of squares of side 10, in zig-zag order.
+		grid = [][2]float64{
+			{0, 0}, {0, 10},
+			{10, 0}, {10, 10},
+			{20, 0}, {20, 10},
+			{30, 0}, {30, 10},
+		}
+		// vertices of 2 squares of side 10, along their perimeters.
+		squares = [][2]float64{
+			{0, 0}, {10, 0}, {10, 10}, {0, 10},
+			{20, 0}, {30, 0}, {30, 10}, {20, 10},
+		}
+		// vertices of a convex pentagon, along its perimeter.
+		pentagon = [][2]float64{
+			{0, 0}, {20, 0}, {30, 10}, {20, 20}, {0, 20},
+		}
+	)
+
+	for _, tc := range []struct {
+		kind     ShapeKind
+		vs       [][2]float64
+		contours int
+		area     float32
+	}{
+		{ShapePoints, grid, 0, 0},
+		{ShapeLines, grid, 0, 0},
+		{ShapeTriangles, grid, 2, 100},
+		{ShapeTriangleStrip, grid, 6, 300},
+		{ShapeTriangleFan, pentagon, 3, 500},
+		{ShapeQuads, squares, 2, 200},
+		{ShapeQuadStrip, grid, 3, 300},
+	} {
+		t.Run(fmt.Sprintf("kind=%d", tc.kind), func(t *testing.T) {
+			proc := newProc(100, 100)
+			proc.BeginClip()
+			p := proc.BeginShape(tc.kind)
+			for _, v := range tc.vs {
+				p.Vertex(v[0], v[1])
+			}
+			p.End()
+
+			if tc.contours == 0 {
+				if got := len(proc.stk.mask.shapes); got != 0 {
+					t.Fatalf("invalid number of shapes: got=%d, want=0", got)
+				}
+				return
+			}
+
+			shape := proc.stk.mask.shapes[0]
+			if got, want := len(shape.contours()), tc.contours; got != want {
+				t.Fatalf("invalid number of contours: got=%d, want=%d", got, want)
+			}
+			// polygons do not overlap: their areas add up to the area
+			// of the shape.
+			if got, want := shape.area(), tc.area; got != want {
+				t.Fatalf("invalid area: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestPathContours(t *testing.T) {
+	square := func(p *Path, x, y, s float64, cw bool) {
+		p.Vertex(x, y)
+		if cw {
+			p.Vertex(x+s, y)
+			p.Vertex(x+s, y+s)
+			p.Vertex(x, y+s)
+			return
+		}
+		p.Vertex(x, y+s)
+		p.Vertex(x+s, y+s)
+		p.Vertex(x+s, y)
+	}
+
+	for _, tc := range []struct {
+		name string
+		rule FillRule
+		hole bool // whether the hole is drawn in the opposite direction.
+		area float32
+	}{
+		{"nonzero-same", NonZero, false, 900 + 100},
+		{"nonzero-opposite", NonZero, true, 900 - 100},
+		{"evenodd-same", EvenOdd, false, 900 - 100},
+		{"evenodd-opposite", EvenOdd, true, 900 - 100},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			proc := newProc(100, 100)
+			proc.BeginClip()
+			p := proc.BeginPath()
+			p.FillRule(tc.rule)
+			square(p, 10, 10, 30, true)
+			p.BeginContour()
+			square(p, 20, 20, 10, !tc.hole)
+			p.EndContour()
+			p.Close()
+			p.End()
+
+			shape := proc.stk.mask.shapes[0]
+			if got, want := len(shape.contours()), 2; got != want {
+				t.Fatalf("invalid number of contours: got=%d, want=%d", got, want)
+			}
+			if got, want := shape.area(), tc.area; got != want {
+				t.Fatalf("invalid area: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestPathEvenOdd(t *testing.T) {
+	// windingAt returns the winding number of the segments around pt.
+	windingAt := func(segs segments, pt f32.Point) int {
+		w := 0
+		for _, c := range segs.transform(f32.Affine2D{}).contours() {
+			ps := c.polyline(0)
+			for i, p := range ps {
+				var (
+					q     = ps[(i+1)%len(ps)]
+					cross = (q.X-p.X)*(pt.Y-p.Y) - (pt.X-p.X)*(q.Y-p.Y)
+				)
+				switch {
+				case p.Y <= pt.Y && q.Y > pt.Y && cross > 0:
+					w++
+				case p.Y > pt.Y && q.Y <= pt.Y && cross < 0:
+					w--
+				}
+			}
+		}
+		return w
+	}
+
+	proc := newProc(100, 100)
+	proc.BeginClip()
+
+	// a pentagram crosses itself: its center is enclosed twice.
+	star := proc.BeginPath()
+	star.FillRule(EvenOdd)
+	for i := 0; i < 5; i++ {
+		sin, cos := math.Sincos(-0.5*math.Pi + float64(i)*4*math.Pi/5)
+		star.Vertex(50+40*cos, 50+40*sin)
+	}
+	star.Close()
+	star.End()
+
+	// crossing squares, neither starting inside the other: their overlap
+	// is enclosed twice.
+	squares := proc.BeginPath()
+	squares.FillRule(EvenOdd)
+	for _, v := range [][2]float64{{10, 10}, {50, 10}, {50, 50}, {10, 50}} {
+		squares.Vertex(v[0], v[1])
+	}
+	squares.BeginContour()
+	for _, v := range [][2]float64{{60, 30}, {60, 70}, {30, 70}, {30, 30}} {
+		squares.Vertex(v[0], v[1])
+	}
+	squares.EndContour()
+	squares.Close()
+	squares.End()
+
+	for _, tc := range []struct {
+		name string
+		path *Path
+		id   int
+		x, y float64
+		want bool
+	}{
+		{"star-center", star, 0, 50, 50, false},
+		{"star-tip", star, 0, 50, 15, true},
+		{"star-outside", star, 0, 10, 90, false},
+		{"squares-overlap", squares, 1, 40, 40, false},
+		{"squares-single", squares, 1, 20, 20, true},
+		{"squares-other", squares, 1, 55, 60, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := windingAt(proc.stk.mask.shapes[tc.id], f32.Pt(float32(tc.x), float32(tc.y)))
+			if got := w != 0; got != tc.want {
+				t.Fatalf("invalid fill: got=%v (winding=%d), want=%v", got, w, tc.want)
+			}
+			if got := tc.path.Contains(tc.x, tc.y); got != tc.want {
+				t.Fatalf("invalid containment: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPathCurveVertex(t *testing.T) {
+	proc := newProc(100, 100)
+	proc.CurveTightness(1)
+	proc.BeginClip()
+
+	// a closed loop of straight Catmull-Rom segments around a square.
+	p := proc.BeginPath()
+	for _, v := range [][2]float64{
+		{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}, {10, 0}, {10, 10},
+	} {
+		p.CurveVertex(v[0], v[1])
+	}
+	p.Close()
+	p.End()
+
+	shape := proc.stk.mask.shapes[0]
+	if got, want := len(shape), 1+4+1; got != want {
+		t.Fatalf("invalid number of segments: got=%d, want=%d", got, want)
+	}
+	if got, want := shape.area(), float32(100); got != want {
+		t.Fatalf("invalid area: got=%v, want=%v", got, want)
+	}
+
+	for _, tc := range []struct {
+		tau        float64
+		ctl0, ctl1 f32.Point
+	}{
+		{0, f32.Pt(10+10./6, 10./6), f32.Pt(10+10./6, 10-10./6)},
+		{0.5, f32.Pt(10+10./3, 10./3), f32.Pt(10+10./3, 10-10./3)},
+	} {
+		t.Run(fmt.Sprintf("tau=%v", tc.tau), func(t *testing.T) {
+			proc := newProc(100, 100)
+			proc.CurveTightness(tc.tau)
+			proc.BeginClip()
+
+			p := proc.BeginPath()
+			for _, v := range [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}} {
+				p.CurveVertex(v[0], v[1])
+			}
+			p.End()
+
+			shape := proc.stk.mask.shapes[0]
+			if got, want := len(shape), 2; got != want {
+				t.Fatalf("invalid number of segments: got=%d, want=%d", got, want)
+			}
+			seg := shape[1]
+			if got, want := seg.op, segOpCubeTo; got != want {
+				t.Fatalf("invalid segment op: got=%v, want=%v", got, want)
+			}
+			for i, want := range []f32.Point{tc.ctl0, tc.ctl1, f32.Pt(10, 10)} {
+				if got := seg.args[i]; dist(got, want) > 1e-4 {
+					t.Fatalf("invalid point %d: got=%v, want=%v", i, got, want)
+				}
+			}
+
+			for _, v := range []float64{0.25, 0.5, 0.75} {
+				var (
+					x   = proc.CurvePoint(0, 10, 10, 0, v)
+					y   = proc.CurvePoint(0, 0, 10, 10, v)
+					bx  = BezierPoint(10, float64(seg.args[0].X), float64(seg.args[1].X), 10, v)
+					by  = BezierPoint(0, float64(seg.args[0].Y), float64(seg.args[1].Y), 10, v)
+					got = f32.Pt(float32(bx), float32(by))
+				)
+				if want := f32.Pt(float32(x), float32(y)); dist(got, want) > 1e-4 {
+					t.Fatalf("invalid position at t=%v: got=%v, want=%v", v, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPathArc(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		draw func(p *Path)
+		area float64
+	}{
+		{
+			name: "arc",
+			draw: func(p *Path) {
+				p.Vertex(50, 50)
+				p.Arc(50, 50, 20, 10, 0, 0.5*math.Pi)
+			},
+			area: math.Pi * 20 * 10 / 4,
+		},
+		{
+			name: "arc-to",
+			draw: func(p *Path) {
+				p.Vertex(30, 50)
+				p.ArcTo(20, 20, 0, false, true, 70, 50)
+			},
+			area: math.Pi * 20 * 20 / 2,
+		},
+		{
+			name: "arc-to-scaled",
+			draw: func(p *Path) {
+				p.Vertex(30, 50)
+				p.ArcTo(1, 1, 0, true, false, 70, 50)
+			},
+			area: math.Pi * 20 * 20 / 2,
+		},
+		{
+			name: "arc-to-rotated",
+			draw: func(p *Path) {
+				sin, cos := math.Sincos(math.Pi / 6)
+				p.Vertex(50+30*cos, 50+30*sin)
+				p.ArcTo(30, 10, math.Pi/6, false, true, 50-30*cos, 50-30*sin)
+				p.ArcTo(30, 10, math.Pi/6, false, true, 50+30*cos, 50+30*sin)
+			},
+			area: math.Pi * 30 * 10,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			proc := newProc(100, 100)
+			proc.BeginClip()
+			p := proc.BeginPath()
+			tc.draw(p)
+			p.Close()
+			p.End()
+
+			got := float64(proc.stk.mask.shapes[0].area())
+			if math.Abs(got-tc.area) > 1 {
+				t.Fatalf("invalid area: got=%v, want=%v", got, tc.area)
+			}
+		})
+	}
+}
+
+func TestPathSample(t *testing.T) {
+	proc := newProc(100, 100)
+	p := proc.BeginPath()
+	p.Vertex(10, 10)
+	p.Vertex(30, 10)
+	p.Vertex(30, 30)
+	p.Vertex(10, 30)
+	p.Close()
+	p.End()
+
+	xs, ys := p.Sample(9)
+	want := [][2]float64{
+		{10, 10}, {20, 10}, {30, 10}, {30, 20},
+		{30, 30}, {20, 30}, {10, 30}, {10, 20},
+		{10, 10},
+	}
+	if got, want := len(xs), len(want); got != want {
+		t.Fatalf("invalid number of samples: got=%d, want=%d", got, want)
+	}
+	for i, w := range want {
+		if math.Abs(xs[i]-w[0]) > 1e-4 || math.Abs(ys[i]-w[1]) > 1e-4 {
+			t.Fatalf("invalid sample %d: got=(%v, %v), want=%v", i, xs[i], ys[i], w)
+		}
+	}
+
+	if xs, _ := p.Sample(0); xs != nil {
+		t.Fatalf("invalid empty sampling: got=%v", xs)
+	}
+}
+
+func TestDrawPathCache(t *testing.T) {
+	proc := newProc(100, 100)
+	path := proc.NewPath()
+	path.Vertex(10, 10)
+	path.Vertex(90, 10)
+	path.Vertex(50, 90)
+	path.Close()
+	path.End()
+
+	proc.DrawPath(path)
+	cache := path.cache
+	if cache == nil || !cache.filled || !cache.stroked {
+		t.Fatalf("path not cached: %+v", cache)
+	}
+
+	proc.Push()
+	proc.Translate(20, 20)
+	proc.Stroke(color.RGBA{R: 255, A: 255})
+	proc.DrawPath(path)
+	proc.Pop()
+	if path.cache != cache {
+		t.Fatalf("cache not reused")
+	}
+
+	proc.StrokeWidth(5)
+	proc.DrawPath(path)
+	if path.cache == cache {
+		t.Fatalf("cache not invalidated by stroke style")
+	}
+
+	cache = path.cache
+	path.Vertex(10, 50)
+	if path.cache != nil {
+		t.Fatalf("cache not invalidated by path modification")
+	}
+
+	proc.DrawPath(path)
+	if path.cache == nil || path.cache == cache {
+		t.Fatalf("cache not rebuilt")
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// pathDataArgs holds the number of arguments of SVG path data commands.
+var pathDataArgs = map[byte]int{
+	'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6,
+	'S': 4, 'Q': 4, 'T': 2, 'A': 7, 'Z': 0,
+}
+
+// ParsePathData parses the SVG path data, such as "M 10 10 L 20 10 Z",
+// and returns the path it describes, in user coordinates.
+//
+// All commands are supported, in their absolute and relative forms:
+// M, L, H, V, C, S, Q, T, A and Z.
+// The returned path is retained, as if created with NewPath.
+func (p *Proc) ParsePathData(data string) (*Path, error) {
+	path := p.NewPath()
+	err := parsePathData(path, data)
+	if err != nil {
+		return nil, err
+	}
+	path.End()
+	return path, nil
+}
+
+// parsePathData appends the shape described by the SVG path data to path.
+func parsePathData(path *Path, data string) error {
+	var (
+		sc = pathDataScanner{s: data}
+
+		cmd    byte    // current command.
+		prev   byte    // previous command, in upper-case.
+		x, y   float64 // current point.
+		x0, y0 float64 // start of the current sub-path.
+		cx, cy float64 // last control point, for S and T commands.
+		closed bool    // whether the current sub-path was closed.
+	)
+
+	for {
+		sc.skip()
+		if sc.eof() {
+			break
+		}
+		switch c := sc.s[sc.pos]; {
+		case strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0:
+			cmd = c
+			sc.pos++
+		case cmd == 0 || cmd == 'Z' || cmd == 'z' || !sc.number():
+			return fmt.Errorf("p5: invalid path data %q at offset %d", c, sc.pos)
+		}
+
+		var (
+			up     = cmd &^ 0x20 // upper-case command.
+			ox, oy float64       // origin of relative coordinates.
+		)
+		if cmd != up {
+			ox, oy = x, y
+		}
+		if prev == 0 && up != 'M' {
+			return fmt.Errorf("p5: path data must start with a move-to command")
+		}
+		if closed && up != 'M' {
+			// drawing after a close-path starts a new sub-path.
+			path.moveTo(x0, y0)
+		}
+		closed = false
+
+		var args []float64
+		for i := 0; i < pathDataArgs[up]; i++ {
+			var (
+				v   float64
+				err error
+			)
+			switch {
+			case up == 'A' && (i == 3 || i == 4):
+				v, err = sc.flag()
+			default:
+				v, err = sc.float()
+			}
+			if err != nil {
+				return fmt.Errorf("p5: could not parse %q path data command: %w", cmd, err)
+			}
+			args = append(args, v)
+		}
+
+		switch up {
+		case 'M':
+			x, y = ox+args[0], oy+args[1]
+			x0, y0 = x, y
+			path.moveTo(x, y)
+			// subsequent pairs of coordinates are implicit line-to commands.
+			cmd = 'L' | cmd&0x20
+		case 'L':
+			x, y = ox+args[0], oy+args[1]
+			path.Vertex(x, y)
+		case 'H':
+			x = ox + args[0]
+			path.Vertex(x, y)
+		case 'V':
+			y = oy + args[0]
+			path.Vertex(x, y)
+		case 'C':
+			cx, cy = ox+args[2], oy+args[3]
+			x1, y1 := ox+args[0], oy+args[1]
+			x, y = ox+args[4], oy+args[5]
+			path.Cube(x1, y1, cx, cy, x, y)
+		case 'S':
+			x1, y1 := x, y
+			if prev == 'C' || prev == 'S' {
+				x1, y1 = 2*x-cx, 2*y-cy
+			}
+			cx, cy = ox+args[0], oy+args[1]
+			x, y = ox+args[2], oy+args[3]
+			path.Cube(x1, y1, cx, cy, x, y)
+		case 'Q':
+			cx, cy = ox+args[0], oy+args[1]
+			x, y = ox+args[2], oy+args[3]
+			path.Quad(cx, cy, x, y)
+		case 'T':
+			if prev == 'Q' || prev == 'T' {
+				cx, cy = 2*x-cx, 2*y-cy
+			} else {
+				cx, cy = x, y
+			}
+			x, y = ox+args[0], oy+args[1]
+			path.Quad(cx, cy, x, y)
+		case 'A':
+			x, y = ox+args[5], oy+args[6]
+			rot := args[2] * math.Pi / 180
+			path.ArcTo(args[0], args[1], rot, args[3] != 0, args[4] != 0, x, y)
+		case 'Z':
+			path.Close()
+			x, y = x0, y0
+			closed = true
+		}
+		prev = up
+	}
+	return nil
+}
+
+// PathData returns the SVG path data describing the path, in user
+// coordinates.
+//
+// Arcs are described with cubic Bézier curves.
+func (p *Path) PathData() string {
+	var (
+		o   strings.Builder
+		num = func(vs ...float32) {
+			for _, v := range vs {
+				o.WriteByte(' ')
+				o.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+			}
+		}
+	)
+	for i, seg := range p.user() {
+		if i > 0 {
+			o.WriteByte(' ')
+		}
+		switch seg.op {
+		case segOpMoveTo:
+			o.WriteByte('M')
+			num(seg.args[0].X, seg.args[0].Y)
+		case segOpLineTo:
+			o.WriteByte('L')
+			num(seg.args[0].X, seg.args[0].Y)
+		case segOpQuadTo:
+			o.WriteByte('Q')
+			num(seg.args[0].X, seg.args[0].Y, seg.args[1].X, seg.args[1].Y)
+		case segOpCubeTo:
+			o.WriteByte('C')
+			num(
+				seg.args[0].X, seg.args[0].Y,
+				seg.args[1].X, seg.args[1].Y,
+				seg.args[2].X, seg.args[2].Y,
+			)
+		case segOpClose:
+			o.WriteByte('Z')
+		}
+	}
+	return o.String()
+}
+
+// pathDataScanner scans the numbers and flags of SVG path data.
+type pathDataScanner struct {
+	s   string
+	pos int
+}
+
+func (sc *pathDataScanner) eof() bool {
+	return sc.pos >= len(sc.s)
+}
+
+// skip skips white space and commas.
+func (sc *pathDataScanner) skip() {
+	for !sc.eof() {
+		switch sc.s[sc.pos] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			sc.pos++
+		default:
+			return
+		}
+	}
+}
+
+// number returns whether a number starts at the current position.
+func (sc *pathDataScanner) number() bool {
+	if sc.eof() {
+		return false
+	}
+	switch c := sc.s[sc.pos]; {
+	case c >= '0' && c <= '9', c == '-', c == '+', c == '.':
+		return true
+	}
+	return false
+}
+
+// float scans a number, such as "-1.5e3", ".5" or "10".
+func (sc *pathDataScanner) float() (float64, error) {
+	sc.skip()
+	var (
+		beg    = sc.pos
+		digits = func() int {
+			n := 0
+			for !sc.eof() && sc.s[sc.pos] >= '0' && sc.s[sc.pos] <= '9' {
+				sc.pos++
+				n++
+			}
+			return n
+		}
+		sign = func() {
+			if !sc.eof() && (sc.s[sc.pos] == '-' || sc.s[sc.pos] == '+') {
+				sc.pos++
+			}
+		}
+	)
+	sign()
+	n := digits()
+	if !sc.eof() && sc.s[sc.pos] == '.' {
+		sc.pos++
+		n += digits()
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid number at offset %d", beg)
+	}
+	if !sc.eof() && (sc.s[sc.pos] == 'e' || sc.s[sc.pos] == 'E') {
+		exp := sc.pos
+		sc.pos++
+		sign()
+		if digits() == 0 {
+			// not an exponent, but the start of the next command.
+			sc.pos = exp
+		}
+	}
+	return strconv.ParseFloat(sc.s[beg:sc.pos], 64)
+}
+
+// flag scans an arc flag, either "0" or "1".
+func (sc *pathDataScanner) flag() (float64, error) {
+	sc.skip()
+	if sc.eof() {
+		return 0, fmt.Errorf("missing flag at offset %d", sc.pos)
+	}
+	switch sc.s[sc.pos] {
+	case '0':
+		sc.pos++
+		return 0, nil
+	case '1':
+		sc.pos++
+		return 1, nil
+	}
+	return 0, fmt.Errorf("invalid flag %q at offset %d", sc.s[sc.pos], sc.pos)
+}
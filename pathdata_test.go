@@ -0,0 +1,125 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"math"
+	"testing"
+
+	"gioui.org/f32"
+)
+
+func TestParsePathData(t *testing.T) {
+	proc := newProc(200, 200)
+	for _, tc := range []struct {
+		name string
+		data string
+		want string
+		area float64
+	}{
+		{
+			name: "abs",
+			data: "M 10 10 L 30 10 L 30 30 L 10 30 Z",
+			want: "M 10 10 L 30 10 L 30 30 L 10 30 Z",
+			area: 400,
+		},
+		{
+			name: "rel",
+			data: "m10,10 l20,0 0,20 -20,0z",
+			want: "M 10 10 L 30 10 L 30 30 L 10 30 Z",
+			area: 400,
+		},
+		{
+			name: "hv",
+			data: "M10 10H30V30h-20v-20z",
+			want: "M 10 10 L 30 10 L 30 30 L 10 30 L 10 10 Z",
+			area: 400,
+		},
+		{
+			name: "compact-numbers",
+			data: "M.5.5L10.5-.5e1 1e1,10Z",
+			want: "M 0.5 0.5 L 10.5 -5 L 10 10 Z",
+			area: 0.5 * math.Abs((10.5-0.5)*(10-0.5)-(10-0.5)*(-5-0.5)),
+		},
+		{
+			name: "implicit-line-to",
+			data: "M 10 10 30 10 30 30 Z",
+			want: "M 10 10 L 30 10 L 30 30 Z",
+			area: 200,
+		},
+		{
+			name: "smooth-cubic",
+			data: "M 10 50 C 10 10 50 10 50 50 S 90 90 90 50",
+			want: "M 10 50 C 10 10 50 10 50 50 C 50 90 90 90 90 50",
+		},
+		{
+			name: "smooth-quad",
+			data: "M 10 50 Q 30 10 50 50 T 90 50",
+			want: "M 10 50 Q 30 10 50 50 Q 70 90 90 50",
+		},
+		{
+			name: "sub-paths",
+			data: "M 0 0 L 10 0 L 10 10 Z L 0 10 L 10 10 Z",
+			want: "M 0 0 L 10 0 L 10 10 Z M 0 0 L 0 10 L 10 10 Z",
+			area: 0,
+		},
+		{
+			name: "arc",
+			data: "M 30 50 A 20 20 0 0 1 70 50 Z",
+			area: math.Pi * 20 * 20 / 2,
+		},
+		{
+			name: "arc-compact-flags",
+			data: "M30 50a20 20 0 0140 0z",
+			area: math.Pi * 20 * 20 / 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := proc.ParsePathData(tc.data)
+			if err != nil {
+				t.Fatalf("could not parse path data: %+v", err)
+			}
+			if tc.want != "" {
+				if got := p.PathData(); got != tc.want {
+					t.Fatalf("invalid path data:\ngot= %q\nwant=%q", got, tc.want)
+				}
+			}
+			if tc.area != 0 {
+				got := math.Abs(float64(p.shape().transform(f32.Affine2D{}).area()))
+				if math.Abs(got-tc.area) > 1 {
+					t.Fatalf("invalid area: got=%v, want=%v", got, tc.area)
+				}
+			}
+
+			// path data round-trips.
+			q, err := proc.ParsePathData(p.PathData())
+			if err != nil {
+				t.Fatalf("could not parse path data back: %+v", err)
+			}
+			if got, want := q.PathData(), p.PathData(); got != want {
+				t.Fatalf("invalid round-trip:\ngot= %q\nwant=%q", got, want)
+			}
+		})
+	}
+}
+
+func TestParsePathDataErrors(t *testing.T) {
+	proc := newProc(200, 200)
+	for _, data := range []string{
+		"L 10 10",
+		"M 10",
+		"M 10 10 X 20 20",
+		"M 10 10 Z 20 20",
+		"M 10 10 A 5 5 0 2 0 20 20",
+		"M 10 10 L 1e",
+	} {
+		t.Run(data, func(t *testing.T) {
+			_, err := proc.ParsePathData(data)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
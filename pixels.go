@@ -0,0 +1,153 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"runtime"
+	"sync"
+
+	"gioui.org/io/event"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// LoadPixels captures the content of the canvas drawn so far in the
+// current frame and returns it.
+//
+// The returned image holds the pixels read by Get and modified by Set,
+// until the next call to LoadPixels. Changes to the image are drawn on
+// the canvas with UpdatePixels.
+func (p *Proc) LoadPixels() (*image.RGBA, error) {
+	if p.head == nil {
+		return nil, fmt.Errorf("p5: no canvas to load pixels from")
+	}
+	img, err := p.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	p.pix = img
+	return img, nil
+}
+
+// UpdatePixels replaces the content of the canvas with the pixels loaded
+// with LoadPixels, and modified since.
+// Pixels are drawn as is, regardless of the current transformation and
+// clipping masks.
+func (p *Proc) UpdatePixels() {
+	if p.stk.mask != nil || p.pix == nil {
+		return
+	}
+
+	// the canvas is rendered at the end of the frame: draw the pixels as
+	// they are now.
+	img := image.NewRGBA(p.pix.Rect)
+	copy(img.Pix, p.pix.Pix)
+
+	// the pixels hold all the content drawn so far: discard it, rather
+	// than drawing over it, then restore the state of the stack.
+	ops := p.ctx.Ops
+	ops.Reset()
+	if p.frame.size != (image.Point{}) {
+		p.frame.clip = clip.Rect{Max: p.frame.size}.Push(ops)
+		event.Op(ops, inputEventTag)
+	}
+	src := paint.NewImageOp(img)
+	src.Filter = paint.FilterNearest
+	src.Add(ops)
+	paint.PaintOp{}.Add(ops)
+	p.stk.rebuild()
+}
+
+// Shade draws over the canvas the colors returned by f for every pixel of
+// the canvas, evaluated at the center of the pixel in user coordinates.
+// A nil color leaves the pixel unchanged.
+//
+// Pixels are drawn as is, regardless of the current transformation.
+// Rows of pixels are evaluated concurrently, across all CPU cores: f
+// must be safe for concurrent use.
+func (p *Proc) Shade(f func(x, y float64) color.Color) {
+	if p.stk.mask != nil {
+		return
+	}
+
+	var (
+		img = image.NewRGBA(image.Rect(0, 0, p.cfg.w, p.cfg.h))
+		n   = min(runtime.GOMAXPROCS(0), p.cfg.h)
+		wg  sync.WaitGroup
+	)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			for py := i; py < p.cfg.h; py += n {
+				y := p.cfg.s2uY(float64(py) + 0.5)
+				for px := 0; px < p.cfg.w; px++ {
+					c := f(p.cfg.s2uX(float64(px)+0.5), y)
+					if c == nil {
+						continue
+					}
+					img.Set(px, py, c)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	p.paintPixels(img)
+}
+
+// paintPixels paints img over the canvas, with its upper-left corner at
+// the upper-left corner of the canvas, regardless of the current
+// transformation.
+func (p *Proc) paintPixels(img *image.RGBA) {
+	defer op.Affine(p.stk.cur().aff.Invert()).Push(p.stk.ops).Pop()
+	src := paint.NewImageOp(img)
+	src.Filter = paint.FilterNearest
+	src.Add(p.stk.ops)
+	paint.PaintOp{}.Add(p.stk.ops)
+}
+
+// Get returns the color of the pixel at (x,y), in pixels from the
+// upper-left corner of the canvas.
+//
+// Get reads the pixels loaded by the last call to LoadPixels, and loads
+// them first if needed. Pixels outside of the canvas are transparent.
+func (p *Proc) Get(x, y int) color.Color {
+	if !p.loadedPixels() {
+		return color.RGBA{}
+	}
+	return p.pix.RGBAAt(x, y)
+}
+
+// Set sets the color of the pixel at (x,y), in pixels from the upper-left
+// corner of the canvas.
+//
+// Set modifies the pixels loaded by the last call to LoadPixels, and loads
+// them first if needed. The canvas shows the change after UpdatePixels.
+// Pixels outside of the canvas are ignored.
+func (p *Proc) Set(x, y int, c color.Color) {
+	if !p.loadedPixels() {
+		return
+	}
+	p.pix.Set(x, y, c)
+}
+
+// loadedPixels loads the pixels of the canvas, unless already loaded, and
+// returns whether they are available.
+func (p *Proc) loadedPixels() bool {
+	if p.pix != nil {
+		return true
+	}
+	_, err := p.LoadPixels()
+	if err != nil {
+		log.Printf("%+v", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,257 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestPixels(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	var (
+		red  = color.RGBA{R: 255, A: 255}
+		gray = color.RGBA{R: 220, G: 220, B: 220, A: 255}
+	)
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			proc.Background(gray)
+		},
+		func(proc *Proc) {
+			proc.Fill(red)
+			proc.Stroke(nil)
+			proc.Rect(50, 50, 100, 100)
+
+			img, err := proc.LoadPixels()
+			if err != nil {
+				t.Errorf("could not load pixels: %+v", err)
+				return
+			}
+			if got, want := img.Bounds(), image.Rect(0, 0, w, h); got != want {
+				t.Errorf("invalid bounds: got=%v, want=%v", got, want)
+			}
+			for _, tc := range []struct {
+				x, y int
+				want color.Color
+			}{
+				{10, 10, gray},
+				{100, 100, red},
+				{-1, 10, color.RGBA{}},
+				{10, h, color.RGBA{}},
+			} {
+				if got := proc.Get(tc.x, tc.y); got != tc.want {
+					t.Errorf("invalid pixel at (%d,%d): got=%v, want=%v", tc.x, tc.y, got, tc.want)
+				}
+			}
+
+			// invert the colors of the upper-left quarter.
+			for y := 0; y < h/2; y++ {
+				for x := 0; x < w/2; x++ {
+					c := proc.Get(x, y).(color.RGBA)
+					proc.Set(x, y, color.RGBA{R: 255 - c.R, G: 255 - c.G, B: 255 - c.B, A: 255})
+				}
+			}
+			proc.Set(w, 0, red) // ignored.
+
+			// pixels are drawn regardless of the current transformation.
+			proc.Translate(20, 20)
+			proc.UpdatePixels()
+		},
+		"testdata/pixels.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestPixelsGraphics(t *testing.T) {
+	g, err := newProc(100, 100).CreateGraphics(10, 10)
+	if err != nil {
+		t.Fatalf("could not create graphics: %+v", err)
+	}
+	defer g.Release()
+
+	var (
+		red   = color.RGBA{R: 255, A: 255}
+		green = color.RGBA{G: 255, A: 255}
+	)
+	g.Background(red)
+
+	// pixels are loaded on first access.
+	if got := g.Get(5, 5); got != red {
+		t.Fatalf("invalid pixel: got=%v, want=%v", got, red)
+	}
+	g.Set(5, 5, green)
+	if got := g.Get(5, 5); got != green {
+		t.Fatalf("invalid pixel: got=%v, want=%v", got, green)
+	}
+
+	g.UpdatePixels()
+	img := g.Image()
+	if got := color.RGBAModel.Convert(img.At(5, 5)); got != green {
+		t.Fatalf("pixels not updated: got=%v, want=%v", got, green)
+	}
+
+	// reloading the pixels discards changes.
+	g.Set(1, 1, green)
+	pix, err := g.LoadPixels()
+	if err != nil {
+		t.Fatalf("could not load pixels: %+v", err)
+	}
+	if got := pix.RGBAAt(1, 1); got != red {
+		t.Fatalf("invalid reloaded pixel: got=%v, want=%v", got, red)
+	}
+
+	// pixels loaded in a previous frame are not reused.
+	g.Image()
+	g.Background(red)
+	if got := g.Get(5, 5); got != red {
+		t.Fatalf("stale pixel: got=%v, want=%v", got, red)
+	}
+	g.Clear()
+	if got := g.Get(5, 5); got != (color.RGBA{}) {
+		t.Fatalf("stale pixel after clear: got=%v", got)
+	}
+}
+
+func TestUpdatePixelsReplace(t *testing.T) {
+	g, err := newProc(100, 100).CreateGraphics(40, 40)
+	if err != nil {
+		t.Fatalf("could not create graphics: %+v", err)
+	}
+	defer g.Release()
+
+	var (
+		red  = color.RGBA{R: 255, A: 255}
+		blue = color.RGBA{B: 255, A: 255}
+	)
+
+	// translucent and antialiased pixels are not blended with themselves.
+	g.Fill(color.NRGBA{R: 255, A: 128})
+	g.Stroke(nil)
+	g.Circle(20, 20, 30)
+	want, err := g.LoadPixels()
+	if err != nil {
+		t.Fatalf("could not load pixels: %+v", err)
+	}
+
+	// transformations and clipping masks are kept.
+	g.Push()
+	g.Translate(20, 0)
+	g.BeginClip()
+	g.Rect(0, 0, 10, 40)
+	g.EndClip()
+
+	g.UpdatePixels()
+
+	g.Fill(red)
+	g.Rect(-20, 0, 40, 5)
+	g.Pop()
+	g.Fill(blue)
+	g.Rect(0, 35, 40, 5)
+
+	img := g.Image().(*image.RGBA)
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			want := want.RGBAAt(x, y)
+			switch {
+			case y < 5 && x >= 20 && x < 30:
+				want = red
+			case y >= 35:
+				want = blue
+			}
+			if got := img.RGBAAt(x, y); got != want {
+				t.Fatalf("invalid pixel at (%d,%d): got=%v, want=%v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestShade(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			proc.PhysCanvas(w, h, -1, 1, 1, -1)
+			proc.Background(color.Gray{Y: 220})
+		},
+		func(proc *Proc) {
+			proc.Translate(0.5, 0.5)
+			proc.Shade(func(x, y float64) color.Color {
+				r := math.Hypot(x, y)
+				if r > 0.9 {
+					return nil
+				}
+				// upper-right quadrant in red, lower-left one in blue.
+				return color.RGBA{
+					R: uint8(255 * math.Max(0, x+y) / 2),
+					B: uint8(255 * math.Max(0, -x-y) / 2),
+					G: uint8(255 * (1 - r/0.9)),
+					A: 255,
+				}
+			})
+		},
+		"testdata/shade.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestShadeCoords(t *testing.T) {
+	g, err := newProc(100, 100).CreateGraphics(20, 10)
+	if err != nil {
+		t.Fatalf("could not create graphics: %+v", err)
+	}
+	defer g.Release()
+
+	var (
+		red  = color.RGBA{R: 255, A: 255}
+		blue = color.RGBA{B: 255, A: 255}
+	)
+	g.Background(blue)
+	g.Shade(func(x, y float64) color.Color {
+		switch {
+		case x < 0 || x > 20 || y < 0 || y > 10:
+			t.Errorf("pixel outside of the canvas: (%v,%v)", x, y)
+		case x-math.Floor(x) != 0.5 || y-math.Floor(y) != 0.5:
+			t.Errorf("pixel not evaluated at its center: (%v,%v)", x, y)
+		case x > 10 && y < 5:
+			return red
+		}
+		return nil
+	})
+
+	img := g.Image()
+	for _, tc := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{15, 2, red},
+		{10, 4, red},
+		{9, 2, blue},
+		{15, 5, blue},
+		{2, 8, blue},
+	} {
+		if got := color.RGBAModel.Convert(img.At(tc.x, tc.y)); got != tc.want {
+			t.Errorf("invalid pixel at (%d,%d): got=%v, want=%v", tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestLoadPixelsNoCanvas(t *testing.T) {
+	proc := newProc(10, 10)
+	_, err := proc.LoadPixels()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
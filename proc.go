@@ -13,6 +13,7 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
@@ -24,6 +25,7 @@ import (
 	"gioui.org/f32"
 	"gioui.org/font"
 	"gioui.org/font/gofont"
+	"gioui.org/font/opentype"
 	"gioui.org/gpu/headless"
 	"gioui.org/io/event"
 	"gioui.org/io/input"
@@ -36,6 +38,7 @@ import (
 	"gioui.org/text"
 	"gioui.org/unit"
 	"gioui.org/widget/material"
+	"gioui.org/x/stroke"
 	"golang.org/x/exp/rand"
 	"golang.org/x/image/bmp"
 	"golang.org/x/image/tiff"
@@ -103,13 +106,19 @@ type Proc struct {
 		s2uX func(v float64) float64 // translate from system- to user coords
 		s2uY func(v float64) float64 // translate from system- to user coords
 
-		th *material.Theme
+		th    *material.Theme
+		fonts []font.FontFace // fonts collection of the text shaper.
 	}
 
-	ctx  layout.Context
-	stk  *stackOps
-	head *headless.Window
-	rand *rand.Rand
+	ctx   layout.Context
+	stk   *stackOps
+	head  *headless.Window
+	pix   *image.RGBA // pixels loaded with LoadPixels.
+	rand  *rand.Rand
+	frame struct {
+		size image.Point // size of the window frame being drawn, if any.
+		clip clip.Stack
+	}
 
 	newWindow func(opts ...app.Option) gioWindow
 }
@@ -134,9 +143,8 @@ func newProc(w, h int) *Proc {
 	proc.ctl.loop = true
 	proc.stk = newStackOps(proc.ctx.Ops)
 
-	th := material.NewTheme()
-	th.Shaper = text.NewShaper(text.WithCollection(gofont.Collection()))
-	proc.cfg.th = th
+	proc.cfg.th = material.NewTheme()
+	proc.LoadFonts(gofont.Collection())
 	proc.initCanvas(w, h, defaultTextFont)
 	proc.stk.cur().stroke.style.width = 2
 
@@ -149,8 +157,13 @@ func (p *Proc) initCanvas(w, h int, fnt font.Font) {
 	p.stk.cur().fill = defaultFillColor
 	p.stk.cur().stroke.color = defaultStrokeColor
 
+	p.stk.cur().rectMode = ModeCorner
+	p.stk.cur().ellipseMode = ModeCenter
+	p.stk.cur().imageMode = ModeCorner
+
 	p.stk.cur().text.color = defaultTextColor
-	p.stk.cur().text.align = text.Start
+	p.stk.cur().text.halign = AlignLeft
+	p.stk.cur().text.valign = AlignBaseline
 	p.stk.cur().text.size = defaultTextSize
 	p.stk.cur().text.font = fnt
 }
@@ -170,19 +183,19 @@ func (p *Proc) initCanvasDim(w, h int, xmin, xmax, ymin, ymax float64) {
 	)
 
 	p.cfg.u2sX = func(v float64) float64 {
-		return (v - p.cfg.x.Min) * wdx
+		return (v - xmin) * wdx
 	}
 
 	p.cfg.s2uX = func(v float64) float64 {
-		return (v * dx) + p.cfg.x.Min
+		return (v * dx) + xmin
 	}
 
 	p.cfg.u2sY = func(v float64) float64 {
-		return (v - p.cfg.y.Min) * hdy
+		return (v - ymin) * hdy
 	}
 
 	p.cfg.s2uY = func(v float64) float64 {
-		return (v * dy) + p.cfg.y.Min
+		return (v * dy) + ymin
 	}
 }
 
@@ -326,19 +339,23 @@ func (p *Proc) handleInputEvents(source input.Source) {
 func (p *Proc) draw(e app.FrameEvent) {
 	p.incFrameCount()
 	p.ctx = app.NewContext(p.ctx.Ops, e)
+	p.pix = nil // pixels loaded in a previous frame are stale.
 
 	ops := p.ctx.Ops
 
 	// Required so that mouse event positions are reported
 	// properly on platforms that use custom frame decoration.
-	globalClip := clip.Rect{Max: e.Size}.Push(ops)
+	p.frame.size = e.Size
+	p.frame.clip = clip.Rect{Max: e.Size}.Push(ops)
 
 	clr := rgba(p.stk.cur().bkg)
 	paint.Fill(ops, clr)
 
 	p.handleInputEvents(e.Source)
 	p.Draw()
-	globalClip.Pop()
+	p.stk.reset()
+	p.frame.clip.Pop()
+	p.frame.size = image.Point{}
 
 	e.Frame(ops)
 }
@@ -387,6 +404,13 @@ func (p *Proc) StrokeWidth(v float64) {
 	p.stk.cur().stroke.style.width = float32(v)
 }
 
+// StrokeCap sets the style of the ends of strokes.
+//
+// The default style is stroke.RoundCap.
+func (p *Proc) StrokeCap(cap stroke.StrokeCap) {
+	p.stk.cur().stroke.style.cap = cap
+}
+
 func (p *Proc) doFill() bool {
 	return p.stk.cur().fill != nil
 }
@@ -396,11 +420,43 @@ func (p *Proc) Fill(c color.Color) {
 	p.stk.cur().fill = c
 }
 
-// LoadFonts sets the fonts collection to use for text.
+// LoadFonts sets the fonts collection to use for text, replacing the
+// default Go fonts.
+// Fonts of the collection are selected with TextFont.
 func (p *Proc) LoadFonts(fnt []font.FontFace) {
-	th := material.NewTheme()
-	th.Shaper = text.NewShaper(text.WithCollection(gofont.Collection()))
-	p.cfg.th = th
+	p.cfg.fonts = append([]font.FontFace(nil), fnt...)
+	p.cfg.th.Shaper = text.NewShaper(text.WithCollection(p.cfg.fonts))
+}
+
+// LoadFont reads the TTF, OTF or TTC font file at the provided path and
+// adds its faces to the fonts collection.
+// It returns the font of the first face, to be selected with TextFont.
+func (p *Proc) LoadFont(fname string) (font.Font, error) {
+	raw, err := os.ReadFile(fname)
+	if err != nil {
+		return font.Font{}, fmt.Errorf("p5: could not read font file %q: %w", fname, err)
+	}
+	return p.loadFont(fname, raw)
+}
+
+// LoadFontFS reads the TTF, OTF or TTC font file at the provided path of
+// the fsys file system and adds its faces to the fonts collection.
+// It returns the font of the first face, to be selected with TextFont.
+func (p *Proc) LoadFontFS(fsys fs.FS, fname string) (font.Font, error) {
+	raw, err := fs.ReadFile(fsys, fname)
+	if err != nil {
+		return font.Font{}, fmt.Errorf("p5: could not read font file %q: %w", fname, err)
+	}
+	return p.loadFont(fname, raw)
+}
+
+func (p *Proc) loadFont(fname string, raw []byte) (font.Font, error) {
+	faces, err := opentype.ParseCollection(raw)
+	if err != nil {
+		return font.Font{}, fmt.Errorf("p5: could not parse font file %q: %w", fname, err)
+	}
+	p.LoadFonts(append(p.cfg.fonts, faces...))
+	return faces[0].Font, nil
 }
 
 // TextSize sets the text size.
@@ -412,47 +468,74 @@ func (p *Proc) TextFont(fnt font.Font) {
 	p.stk.cur().text.font = fnt
 }
 
+// TextStyle sets the weight and slant of the current text font.
+// The typeface of the font is kept, while TextFont replaces it all.
+func (p *Proc) TextStyle(style FontStyle) {
+	fnt := &p.stk.cur().text.font
+	fnt.Weight = font.Normal
+	fnt.Style = font.Regular
+	switch style {
+	case StyleBold:
+		fnt.Weight = font.Bold
+	case StyleItalic:
+		fnt.Style = font.Italic
+	case StyleBoldItalic:
+		fnt.Weight = font.Bold
+		fnt.Style = font.Italic
+	}
+}
+
+// TextColor sets the color used to fill text.
+// A nil color disables filling text.
+//
+// The default color is black.
+func (p *Proc) TextColor(c color.Color) {
+	p.stk.cur().text.color = c
+}
+
+// TextStroke sets the color used to outline text, with the current
+// stroke width.
+// A nil color disables outlining text.
+//
+// By default, text is not outlined.
+func (p *Proc) TextStroke(c color.Color) {
+	p.stk.cur().text.stroke = c
+}
+
+// TextAlign sets how text is positioned relative to the point it is
+// drawn at: horizontally with AlignLeft, AlignCenter or AlignRight, and
+// vertically with AlignTop, AlignCenter, AlignBaseline or AlignBottom.
+//
+// The default alignment is AlignLeft and AlignBaseline.
+func (p *Proc) TextAlign(horizontal, vertical TextAlignment) {
+	p.stk.cur().text.halign = horizontal
+	p.stk.cur().text.valign = vertical
+}
+
+// TextLeading sets the spacing between the baselines of lines of text,
+// in the same unit as TextSize.
+// A zero leading selects a spacing proportional to the text size.
+func (p *Proc) TextLeading(leading float64) {
+	p.stk.cur().text.leading = float32(leading)
+}
+
 // Text draws txt on the screen at (x,y).
+// Lines of text are positioned according to the current text alignment.
 func (p *Proc) Text(txt string, x, y float64) {
-	x = p.cfg.u2sX(x)
-	y = p.cfg.u2sY(y)
-
-	var (
-		offset = x
-		w, _   = p.cnvSize()
-		size   = p.stk.cur().text.size
-	)
-	switch p.stk.cur().text.align {
-	case text.End:
-		offset = x - w
-	case text.Middle:
-		offset = x - 0.5*w
+	if p.stk.mask != nil {
+		return
 	}
-	defer op.TransformOp{}.Push(p.ctx.Ops).Pop()
-	op.Affine(f32.Affine2D{}.Offset(f32.Point{
-		X: float32(offset),
-		Y: float32(y) - size,
-	})).Add(p.ctx.Ops) // shift to use baseline
 
-	l := material.Label(p.cfg.th, unit.Sp(size), txt)
-	l.Color = rgba(p.stk.cur().text.color)
-	l.Alignment = p.stk.cur().text.align
-	l.Font = p.stk.cur().text.font
-	l.Layout(p.ctx)
+	params, ox, oy := p.placeText(txt, x, y)
+	p.drawText(txt, params, ox, oy)
 }
 
 // Screenshot saves the current canvas to the provided file.
 // Supported file formats are: PNG, JPEG and GIF.
 func (p *Proc) Screenshot(fname string) error {
-	err := p.head.Frame(p.ctx.Ops)
+	img, err := p.snapshot()
 	if err != nil {
-		return fmt.Errorf("p5: could not run headless frame: %w", err)
-	}
-
-	img := image.NewRGBA(image.Rect(0, 0, p.cfg.w, p.cfg.h))
-	err = p.head.Screenshot(img)
-	if err != nil {
-		return fmt.Errorf("p5: could not take screenshot: %w", err)
+		return err
 	}
 
 	f, err := os.Create(fname)
@@ -491,6 +574,22 @@ func (p *Proc) Screenshot(fname string) error {
 	return nil
 }
 
+// snapshot renders the operations drawn so far on the headless window and
+// returns its content.
+func (p *Proc) snapshot() (*image.RGBA, error) {
+	err := p.head.Frame(p.ctx.Ops)
+	if err != nil {
+		return nil, fmt.Errorf("p5: could not run headless frame: %w", err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, p.cfg.w, p.cfg.h))
+	err = p.head.Screenshot(img)
+	if err != nil {
+		return nil, fmt.Errorf("p5: could not take screenshot: %w", err)
+	}
+	return img, nil
+}
+
 // RandomSeed changes the sequence of numbers generated by Random.
 func (p *Proc) RandomSeed(seed uint64) {
 	p.rand.Seed(seed)
@@ -570,11 +669,32 @@ func (p *Proc) ReadImage(fname string) (img image.Image, err error) {
 	return img, err
 }
 
+// ImageMode sets how the position of images drawn with DrawImage is
+// interpreted.
+// With ModeCenter or ModeRadius, (x,y) is the center of the image.
+// Otherwise, (x,y) is its upper-left corner.
+//
+// The default mode is ModeCorner.
+func (p *Proc) ImageMode(mode ShapeMode) {
+	p.stk.cur().imageMode = mode
+}
+
 // DrawImage draws the provided image at (x,y).
 func (p *Proc) DrawImage(img image.Image, x, y float64) {
+	if p.stk.mask != nil {
+		return
+	}
+
 	p.stk.push()
 	defer p.stk.pop()
 
+	switch p.stk.cur().imageMode {
+	case ModeCenter, ModeRadius:
+		size := img.Bounds().Size()
+		x -= 0.5 * float64(size.X)
+		y -= 0.5 * float64(size.Y)
+	}
+
 	p.stk.translate(x, y)
 	paint.NewImageOp(img).Add(p.stk.ops)
 	paint.PaintOp{}.Add(p.stk.ops)
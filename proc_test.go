@@ -13,11 +13,18 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"gioui.org/app"
+	"gioui.org/font"
+	"gioui.org/font/gofont"
 	"gioui.org/io/event"
 	"gioui.org/op"
+	"gioui.org/text"
+	"github.com/go-fonts/latin-modern/lmroman12regular"
 	"github.com/go-p5/p5/internal/cmpimg"
+	"golang.org/x/image/math/fixed"
+	"gonum.org/v1/gonum/spatial/r2"
 )
 
 var GenerateTestData = flag.Bool("regen", false, "Uses the current state to regenerate the test data.")
@@ -216,6 +223,458 @@ func TestText(t *testing.T) {
 	proc.Run(t)
 }
 
+func TestTextAlign(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			// upward y axis, to check alignment stays relative to the screen.
+			proc.PhysCanvas(w, h, 0, w, h, 0)
+			proc.Background(color.Gray{Y: 220})
+		},
+		func(proc *Proc) {
+			proc.Stroke(color.RGBA{R: 255, A: 255})
+			proc.StrokeWidth(1)
+			proc.Line(100, 195, 100, 100)
+			proc.Line(5, 50, 195, 50)
+			proc.TextSize(16)
+			for i, align := range []TextAlignment{AlignLeft, AlignCenter, AlignRight} {
+				proc.TextAlign(align, AlignBaseline)
+				proc.Text("Align", 100, 180-25*float64(i))
+			}
+			for i, align := range []TextAlignment{AlignTop, AlignCenter, AlignBaseline, AlignBottom} {
+				proc.TextAlign(AlignCenter, align)
+				proc.Text("Ag", 25+50*float64(i), 50)
+			}
+		},
+		"testdata/text-align.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestTextMetrics(t *testing.T) {
+	const tol = 1e-6
+	proc := newProc(200, 200)
+	proc.TextSize(20)
+
+	var (
+		w   = proc.TextWidth("Hello")
+		asc = proc.TextAscent()
+		dsc = proc.TextDescent()
+	)
+	if w <= 0 || asc <= 0 || dsc <= 0 {
+		t.Fatalf("invalid metrics: width=%v, ascent=%v, descent=%v", w, asc, dsc)
+	}
+	if got, want := proc.TextWidth("Hi\nHello"), w; math.Abs(got-want) > tol {
+		t.Fatalf("invalid multi-line width: got=%v, want=%v", got, want)
+	}
+	if got, want := proc.TextWidth("HelloHello"), 2*w; math.Abs(got-want) > 0.5 {
+		t.Fatalf("invalid width: got=%v, want=%v", got, want)
+	}
+
+	for _, tc := range []struct {
+		h, v TextAlignment
+		want r2.Box
+	}{
+		{
+			h: AlignLeft, v: AlignBaseline,
+			want: r2.Box{Min: r2.Vec{X: 50, Y: 50 - asc}, Max: r2.Vec{X: 50 + w, Y: 50 + dsc}},
+		},
+		{
+			h: AlignRight, v: AlignTop,
+			want: r2.Box{Min: r2.Vec{X: 50 - w, Y: 50}, Max: r2.Vec{X: 50, Y: 50 + asc + dsc}},
+		},
+		{
+			h: AlignCenter, v: AlignCenter,
+			want: r2.Box{
+				Min: r2.Vec{X: 50 - w/2, Y: 50 - (asc+dsc)/2},
+				Max: r2.Vec{X: 50 + w/2, Y: 50 + (asc+dsc)/2},
+			},
+		},
+		{
+			h: AlignLeft, v: AlignBottom,
+			want: r2.Box{Min: r2.Vec{X: 50, Y: 50 - asc - dsc}, Max: r2.Vec{X: 50 + w, Y: 50}},
+		},
+	} {
+		proc.TextAlign(tc.h, tc.v)
+		got := proc.TextBounds("Hello", 50, 50)
+		if !boxEqual(got, tc.want, tol) {
+			t.Errorf("invalid bounds for (%d,%d): got=%v, want=%v", tc.h, tc.v, got, tc.want)
+		}
+	}
+
+	// metrics are reported in user coordinates, here with an upward y axis.
+	proc.PhysCanvas(200, 200, 0, 2, 4, 0)
+	proc.TextAlign(AlignLeft, AlignBaseline)
+	if got, want := proc.TextWidth("Hello"), w/100; math.Abs(got-want) > tol {
+		t.Fatalf("invalid physical width: got=%v, want=%v", got, want)
+	}
+	if got, want := proc.TextAscent(), asc/50; math.Abs(got-want) > tol {
+		t.Fatalf("invalid physical ascent: got=%v, want=%v", got, want)
+	}
+	got := proc.TextBounds("Hello", 1, 2)
+	want := r2.Box{Min: r2.Vec{X: 1, Y: 2 - dsc/50}, Max: r2.Vec{X: 1 + w/100, Y: 2 + asc/50}}
+	if !boxEqual(got, want, tol) {
+		t.Fatalf("invalid physical bounds: got=%v, want=%v", got, want)
+	}
+}
+
+func boxEqual(a, b r2.Box, tol float64) bool {
+	return math.Abs(a.Min.X-b.Min.X) <= tol && math.Abs(a.Min.Y-b.Min.Y) <= tol &&
+		math.Abs(a.Max.X-b.Max.X) <= tol && math.Abs(a.Max.Y-b.Max.Y) <= tol
+}
+
+func TestTextBounds(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			proc.Background(color.Gray{Y: 220})
+		},
+		func(proc *Proc) {
+			proc.Stroke(nil)
+			proc.TextSize(20)
+			for i, align := range []TextAlignment{AlignLeft, AlignCenter, AlignRight} {
+				y := 40 + 60*float64(i)
+				proc.TextAlign(align, AlignCenter)
+				box := proc.TextBounds("Label", 100, y)
+				proc.Fill(color.RGBA{B: 255, A: 100})
+				proc.Rect(box.Min.X, box.Min.Y, box.Max.X-box.Min.X, box.Max.Y-box.Min.Y)
+				proc.Text("Label", 100, y)
+			}
+		},
+		"testdata/text-bounds.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestTextBox(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			proc.Background(color.Gray{Y: 220})
+		},
+		func(proc *Proc) {
+			const txt = "The quick brown fox jumps over the lazy dog.\nPack my box."
+			proc.Fill(nil)
+			proc.Stroke(color.RGBA{R: 255, A: 255})
+			proc.StrokeWidth(1)
+			proc.TextSize(12)
+
+			proc.Rect(10, 10, 85, 85)
+			proc.TextBox(txt, 10, 10, 85, 85)
+
+			proc.Rect(105, 10, 85, 85)
+			proc.TextAlign(AlignCenter, AlignCenter)
+			proc.TextLeading(18)
+			proc.TextBox(txt, 105, 10, 85, 85)
+
+			proc.Rect(10, 105, 180, 40)
+			proc.TextAlign(AlignRight, AlignBottom)
+			proc.TextLeading(0)
+			proc.TextBox(txt+" "+txt, 10, 105, 180, 40)
+
+			proc.RectMode(ModeCorners)
+			proc.Rect(10, 155, 190, 190)
+			proc.TextAlign(AlignLeft, AlignBaseline)
+			proc.TextBox("Corners", 10, 155, 190, 190)
+		},
+		"testdata/text-box.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestTextLayout(t *testing.T) {
+	const txt = "one two three four five six"
+	proc := newProc(200, 200)
+	proc.TextSize(20)
+
+	var (
+		line = proc.layoutText(txt)
+		wrap = proc.shapeText(txt, proc.textParams(int(line.width/2), 0))
+	)
+	if got, want := len(line.lines), 1; got != want {
+		t.Fatalf("invalid number of lines: got=%d, want=%d", got, want)
+	}
+	if len(wrap.lines) < 2 || wrap.width > line.width/2 {
+		t.Fatalf("text not wrapped: lines=%d, width=%v", len(wrap.lines), wrap.width)
+	}
+
+	proc.TextLeading(30)
+	lay := proc.layoutText("one\ntwo\nthree")
+	if got, want := len(lay.lines), 3; got != want {
+		t.Fatalf("invalid number of lines: got=%d, want=%d", got, want)
+	}
+	if got, want := lay.lines[2]-lay.lines[1], 30.0; math.Abs(got-want) > 1 {
+		t.Fatalf("invalid leading: got=%v, want=%v", got, want)
+	}
+
+	trunc := proc.shapeText(txt, proc.textParams(int(line.width/2), 1))
+	if got, want := len(trunc.lines), 1; got != want {
+		t.Fatalf("invalid number of truncated lines: got=%d, want=%d", got, want)
+	}
+}
+
+func TestTextStyle(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			proc.Background(color.Gray{Y: 220})
+		},
+		func(proc *Proc) {
+			proc.TextSize(20)
+			for i, style := range []FontStyle{StyleNormal, StyleBold, StyleItalic, StyleBoldItalic} {
+				proc.TextStyle(style)
+				proc.TextColor(color.RGBA{B: 255 - 60*uint8(i), A: 255})
+				proc.Text("Style", 10, 30+25*float64(i))
+			}
+
+			proc.TextStyle(StyleBold)
+			proc.TextSize(40)
+			proc.TextColor(nil)
+			proc.TextStroke(color.RGBA{R: 255, A: 255})
+			proc.StrokeWidth(1)
+			proc.Text("Outline", 10, 150)
+
+			proc.TextColor(color.RGBA{G: 200, A: 255})
+			proc.TextStroke(color.Black)
+			proc.StrokeWidth(2)
+			proc.Text("Both", 10, 190)
+		},
+		"testdata/text-style.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestTextFontStyle(t *testing.T) {
+	proc := newProc(200, 200)
+	proc.TextFont(font.Font{Typeface: "Go"})
+	normal := proc.TextWidth("Hello, World!")
+
+	proc.TextStyle(StyleBoldItalic)
+	fnt := proc.stk.cur().text.font
+	if fnt.Typeface != "Go" || fnt.Weight != font.Bold || fnt.Style != font.Italic {
+		t.Fatalf("invalid font: %+v", fnt)
+	}
+	if bold := proc.TextWidth("Hello, World!"); bold <= normal {
+		t.Fatalf("bold text not wider: bold=%v, normal=%v", bold, normal)
+	}
+
+	proc.TextStyle(StyleItalic)
+	fnt = proc.stk.cur().text.font
+	if fnt.Weight != font.Normal || fnt.Style != font.Italic {
+		t.Fatalf("invalid font: %+v", fnt)
+	}
+
+	proc.TextStyle(StyleNormal)
+	if got := proc.TextWidth("Hello, World!"); got != normal {
+		t.Fatalf("invalid width: got=%v, want=%v", got, normal)
+	}
+}
+
+func TestTextToPath(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			proc.Background(color.Gray{Y: 220})
+		},
+		func(proc *Proc) {
+			proc.TextSize(48)
+			proc.TextAlign(AlignCenter, AlignCenter)
+			proc.Text("Ag?", 100, 60)
+
+			// outlines are drawn exactly over the text.
+			path := proc.TextToPath("Ag?", 100, 60)
+			proc.Fill(nil)
+			proc.Stroke(color.RGBA{R: 255, A: 255})
+			proc.StrokeWidth(1)
+			proc.DrawPath(path)
+
+			proc.Fill(color.RGBA{B: 255, A: 255})
+			proc.Stroke(nil)
+			proc.DrawPath(proc.TextToPath("Go", 100, 150).Offset(2))
+
+			xs, ys := proc.TextToPoints("Go", 100, 150, 0.2)
+			proc.Stroke(color.RGBA{G: 255, A: 255})
+			proc.StrokeWidth(3)
+			for i := range xs {
+				proc.Point(xs[i], ys[i])
+			}
+		},
+		"testdata/text-to-path.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestTextOnPath(t *testing.T) {
+	const (
+		w = 200
+		h = 200
+	)
+	proc := newTestProc(t, w, h,
+		func(proc *Proc) {
+			proc.Background(color.Gray{Y: 220})
+		},
+		func(proc *Proc) {
+			arc := proc.NewPath()
+			arc.Arc(100, 110, 70, 70, math.Pi, 2*math.Pi)
+			arc.End()
+
+			proc.Fill(nil)
+			proc.Stroke(color.Gray{Y: 128})
+			proc.DrawPath(arc)
+
+			proc.TextSize(20)
+			proc.TextAlign(AlignCenter, AlignBaseline)
+			proc.TextOnPath("curved\nlabel", arc, 0.5*arc.Length())
+
+			proc.TextColor(color.RGBA{B: 255, A: 255})
+			proc.TextAlign(AlignLeft, AlignTop)
+			proc.TextOnPath("below the arc, too long to fit", arc, 10)
+
+			wave := proc.NewPath()
+			wave.Vertex(20, 170)
+			wave.Quad(60, 130, 100, 170)
+			wave.Quad(140, 210, 180, 170)
+			wave.End()
+			proc.DrawPath(wave)
+
+			proc.TextColor(color.RGBA{R: 255, A: 255})
+			proc.TextSize(16)
+			proc.TextAlign(AlignRight, AlignCenter)
+			proc.TextOnPath("road", wave, wave.Length()-10)
+			proc.TextAlign(AlignLeft, AlignBottom)
+			proc.TextOnPath("Route 66", wave, 0)
+		},
+		"testdata/text-on-path.png",
+		imgDelta,
+	)
+
+	proc.Run(t)
+}
+
+func TestTextToPathGeometry(t *testing.T) {
+	proc := newProc(200, 200)
+	proc.TextSize(40)
+	proc.TextAlign(AlignCenter, AlignBaseline)
+
+	var (
+		path = proc.TextToPath("Hello", 100, 100)
+		bnd  = path.Bounds()
+		box  = proc.TextBounds("Hello", 100, 100)
+	)
+	if bnd.Min.X < box.Min.X-2 || bnd.Max.X > box.Max.X+2 ||
+		bnd.Min.Y < box.Min.Y || bnd.Max.Y > 100.5 {
+		t.Fatalf("outlines out of the text bounds: outlines=%v, text=%v", bnd, box)
+	}
+	if got, want := 0.5*(bnd.Min.X+bnd.Max.X), 100.0; math.Abs(got-want) > 2 {
+		t.Fatalf("outlines not centered: got=%v, want=%v", got, want)
+	}
+	if !path.Contains(bnd.Min.X+2, 100-1) {
+		t.Fatalf("stem of the H not inside the outlines")
+	}
+	if path.Contains(100, bnd.Min.Y-1) {
+		t.Fatalf("point above the text inside the outlines")
+	}
+
+	xs, ys := proc.TextToPoints("Hello", 100, 100, 0.5)
+	if got, want := len(xs), int(math.Ceil(path.Length()*0.5)); got != want || len(ys) != want {
+		t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+	}
+
+	if empty := proc.TextToPath("", 100, 100); len(empty.segs) != 0 {
+		t.Fatalf("invalid outlines for empty text: %v", empty.segs)
+	}
+}
+
+func TestLoadFont(t *testing.T) {
+	var (
+		proc  = newProc(200, 200)
+		fname = filepath.Join(t.TempDir(), "lmroman12-regular.otf")
+		width = func(fnt font.Font) fixed.Int26_6 {
+			shaper := proc.cfg.th.Shaper
+			shaper.LayoutString(text.Parameters{
+				Font:     fnt,
+				PxPerEm:  fixed.I(20),
+				MaxWidth: 1e6,
+			}, "Hello, World!")
+			var adv fixed.Int26_6
+			for {
+				g, ok := shaper.NextGlyph()
+				if !ok {
+					return adv
+				}
+				adv += g.Advance
+			}
+		}
+	)
+	err := os.WriteFile(fname, lmroman12regular.TTF, 0644)
+	if err != nil {
+		t.Fatalf("could not write font file: %+v", err)
+	}
+
+	ref := width(font.Font{Typeface: "Latin Modern Roman"})
+	fnt, err := proc.LoadFont(fname)
+	if err != nil {
+		t.Fatalf("could not load font: %+v", err)
+	}
+	if fnt.Typeface == "" {
+		t.Fatalf("invalid font: %+v", fnt)
+	}
+	if got := width(fnt); got == ref {
+		t.Fatalf("font not used by the text shaper")
+	}
+	if got, want := len(proc.cfg.fonts), len(gofont.Collection())+1; got != want {
+		t.Fatalf("invalid number of fonts: got=%d, want=%d", got, want)
+	}
+
+	fsys := fstest.MapFS{"fonts/lm.otf": {Data: lmroman12regular.TTF}}
+	got, err := proc.LoadFontFS(fsys, "fonts/lm.otf")
+	if err != nil {
+		t.Fatalf("could not load font from file system: %+v", err)
+	}
+	if got != fnt {
+		t.Fatalf("invalid font: got=%+v, want=%+v", got, fnt)
+	}
+
+	proc.LoadFonts(gofont.Collection())
+	if got, want := len(proc.cfg.fonts), len(gofont.Collection()); got != want {
+		t.Fatalf("invalid number of fonts: got=%d, want=%d", got, want)
+	}
+
+	_, err = proc.LoadFont(filepath.Join(t.TempDir(), "missing.ttf"))
+	if err == nil {
+		t.Fatalf("expected an error for a missing file")
+	}
+	_, err = proc.LoadFontFS(fstest.MapFS{"bad.ttf": {Data: []byte("not a font")}}, "bad.ttf")
+	if err == nil {
+		t.Fatalf("expected an error for an invalid file")
+	}
+}
+
 func TestHelloWorld(t *testing.T) {
 	const (
 		w = 200
@@ -245,7 +704,8 @@ func TestHelloWorld(t *testing.T) {
 
 			p5.Stroke(color.Black)
 			p5.StrokeWidth(5)
-			p5.Arc(300, 100, 80, 20, 0, 1.5*math.Pi)
+			p5.Fill(nil)
+			p5.Arc(300, 100, 160, 40, 0, 1.5*math.Pi, ArcOpen)
 		},
 		"testdata/hello.png",
 		imgDelta,
@@ -5,21 +5,85 @@
 package p5
 
 import (
+	"fmt"
+	"image/color"
 	"math"
 
 	"gioui.org/f32"
 	"gioui.org/op"
+	"gioui.org/op/clip"
 	"gioui.org/op/paint"
+	"gioui.org/x/stroke"
 )
 
+// ShapeMode describes how the position and size parameters of
+// rectangles, ellipses and images are interpreted.
+type ShapeMode uint8
+
+const (
+	// ModeCorner interprets the parameters as the position of the
+	// upper-left corner of the shape, followed by its width and height.
+	ModeCorner ShapeMode = iota
+
+	// ModeCorners interprets the parameters as the positions of two
+	// opposite corners of the shape.
+	ModeCorners
+
+	// ModeCenter interprets the parameters as the position of the
+	// center of the shape, followed by its width and height.
+	ModeCenter
+
+	// ModeRadius interprets the parameters as the position of the
+	// center of the shape, followed by half its width and half its height.
+	ModeRadius
+)
+
+// box returns the upper-left corner and the size of the rectangle
+// described by the (a,b,c,d) parameters.
+func (m ShapeMode) box(a, b, c, d float64) (x, y, w, h float64) {
+	switch m {
+	case ModeCorners:
+		return math.Min(a, c), math.Min(b, d), math.Abs(c - a), math.Abs(d - b)
+	case ModeCenter:
+		return a - 0.5*c, b - 0.5*d, c, d
+	case ModeRadius:
+		return a - c, b - d, 2 * c, 2 * d
+	default:
+		return a, b, c, d
+	}
+}
+
+// RectMode sets how the parameters of Rect and Square are interpreted.
+//
+// The default mode is ModeCorner.
+func (p *Proc) RectMode(mode ShapeMode) {
+	p.stk.cur().rectMode = mode
+}
+
+// EllipseMode sets how the parameters of Ellipse, Circle and Arc are
+// interpreted.
+//
+// The default mode is ModeCenter.
+func (p *Proc) EllipseMode(mode ShapeMode) {
+	p.stk.cur().ellipseMode = mode
+}
+
+// ellipse returns the center and the semi-axes of the ellipse described
+// by the (a,b,c,d) parameters, according to the current ellipse mode.
+func (p *Proc) ellipse(a, b, c, d float64) (x, y, w, h float64) {
+	x, y, w, h = p.stk.cur().ellipseMode.box(a, b, c, d)
+	w *= 0.5
+	h *= 0.5
+	return x + w, y + h, w, h
+}
+
 // Ellipse draws an ellipse at (x,y) with the provided width and height.
 func (p *Proc) Ellipse(x, y, w, h float64) {
-	if !p.doFill() && !p.doStroke() {
+	if !p.doShape() {
 		return
 	}
 
-	w *= 0.5
-	h *= 0.5
+	x, y, w, h = p.ellipse(x, y, w, h)
 
 	var (
 		ec float64
@@ -53,27 +117,8 @@ func (p *Proc) Ellipse(x, y, w, h float64) {
 		return segs
 	}
 
-	if fill := p.stk.cur().fill; fill != nil {
-		stack := op.TransformOp{}.Push(p.ctx.Ops)
-		close := true
-		paint.FillShape(
-			p.ctx.Ops,
-			rgba(fill),
-			path(p.ctx.Ops, close).outline(p.ctx.Ops),
-		)
-		stack.Pop()
-	}
-
-	if stroke := p.stk.cur().stroke.color; stroke != nil {
-		stack := op.TransformOp{}.Push(p.ctx.Ops)
-		close := false
-		paint.FillShape(
-			p.ctx.Ops,
-			rgba(stroke),
-			path(p.ctx.Ops, close).stroke(p.ctx.Ops, p.stk.cur().stroke),
-		)
-		stack.Pop()
-	}
+	p.fillShape(path(p.ctx.Ops, true))
+	p.strokeShape(path(p.ctx.Ops, false))
 }
 
 // Circle draws a circle at (x,y) with a diameter d.
@@ -81,48 +126,65 @@ func (p *Proc) Circle(x, y, d float64) {
 	p.Ellipse(x, y, d, d)
 }
 
+// ArcMode describes how arcs are closed.
+type ArcMode uint8
+
+const (
+	// ArcOpen draws an open arc. The filled area is closed by
+	// a straight line between the ends of the arc.
+	ArcOpen ArcMode = iota
+
+	// ArcChord draws an arc closed by a straight line between its ends.
+	ArcChord
+
+	// ArcPie draws an arc closed by straight lines joining its ends
+	// to the center of the ellipse, like a pie slice.
+	ArcPie
+)
+
 // Arc draws an ellipsoidal arc centered at (x,y), with the provided
 // width and height, and a path from the beg to end radians.
 // Positive angles denote a counter-clockwise path.
-func (p *Proc) Arc(x, y, w, h float64, beg, end float64) {
-	if !p.doStroke() {
+// The mode selects how the arc is closed.
+func (p *Proc) Arc(x, y, w, h float64, beg, end float64, mode ArcMode) {
+	if !p.doShape() {
 		return
 	}
 
+	x, y, w, h = p.ellipse(x, y, w, h)
+
 	var (
-		c  = p.pt(x, y)
-		a  = p.cfg.u2sX(w)
-		b  = p.cfg.u2sY(h)
-		f1 f32.Point
-		f2 f32.Point
+		c        = p.pt(x, y)
+		f1, f2   = p.foci(x, y, w, h)
+		sin, cos = math.Sincos(beg)
+		p0       = p.pt(x+w*cos, y+h*sin)
+		angle    = float32(end - beg)
 	)
-
-	switch {
-	case a >= b:
-		f := math.Sqrt(a*a - b*b)
-		f1 = c.Add(p.pt(+f, 0))
-		f2 = c.Add(p.pt(-f, 0))
-	default:
-		f := math.Sqrt(b*b - a*a)
-		f1 = c.Add(p.pt(0, +f))
-		f2 = c.Add(p.pt(0, -f))
+	if p.mirrored() {
+		angle = -angle
 	}
 
-	var (
-		sin, cos = math.Sincos(beg)
-		p0       = p.pt(a*cos, b*sin).Add(c)
-		path     = segments{
+	path := func(mode ArcMode) segments {
+		segs := segments{
 			opMoveTo(p0),
-			opArcTo(f1, f2, float32(end-beg)),
+			opArcTo(f1, f2, angle),
 		}
-	)
-	defer op.TransformOp{}.Push(p.ctx.Ops).Pop()
+		switch mode {
+		case ArcChord:
+			segs = append(segs, segment{op: segOpClose})
+		case ArcPie:
+			segs = append(segs, opLineTo(c), segment{op: segOpClose})
+		}
+		return segs
+	}
 
-	paint.FillShape(
-		p.ctx.Ops,
-		rgba(p.stk.cur().stroke.color),
-		path.stroke(p.ctx.Ops, p.stk.cur().stroke),
-	)
+	switch mode {
+	case ArcPie:
+		p.fillShape(path(ArcPie))
+	default:
+		p.fillShape(path(ArcChord))
+	}
+	p.strokeShape(path(mode))
 }
 
 // Line draws a line between (x1,y1) and (x2,y2).
@@ -139,12 +201,76 @@ func (p *Proc) Line(x1, y1, x2, y2 float64) {
 			opLineTo(p2),
 		}
 	)
-	defer op.TransformOp{}.Push(p.ctx.Ops).Pop()
+	p.strokeShape(path)
+}
+
+// Point draws a point at (x,y).
+//
+// Points are drawn with the stroke color. Their size is the stroke width,
+// and their shape follows the stroke cap: points are disks with round
+// caps, and squares otherwise.
+func (p *Proc) Point(x, y float64) {
+	p.Points([]float64{x}, []float64{y})
+}
+
+// Points draws a point at each (xs[i],ys[i]) coordinates, as a single shape.
+//
+// Points are drawn with the stroke color. Their size is the stroke width,
+// and their shape follows the stroke cap: points are disks with round
+// caps, and squares otherwise.
+func (p *Proc) Points(xs, ys []float64) {
+	if len(xs) != len(ys) {
+		panic(fmt.Errorf("p5: length mismatch (xs=%d, ys=%d)", len(xs), len(ys)))
+	}
+	if p.stk.mask != nil || !p.doStroke() {
+		return
+	}
+
+	cs := make([]f32.Point, len(xs))
+	for i, x := range xs {
+		cs[i] = p.pt(x, ys[i])
+	}
+	p.points(cs)
+}
+
+// points draws a point at each of the provided system coordinates.
+func (p *Proc) points(cs []f32.Point) {
+	if p.stk.mask != nil || !p.doStroke() {
+		return
+	}
+
+	var (
+		sty  = p.stk.cur().stroke.style
+		r    = 0.5 * sty.width
+		path segments
+	)
+	switch sty.cap {
+	case stroke.RoundCap:
+		path = make(segments, 0, 2*len(cs))
+		for _, c := range cs {
+			path = append(path,
+				opMoveTo(c.Add(f32.Pt(r, 0))),
+				opArcTo(c, c, 2*math.Pi),
+			)
+		}
+	default:
+		path = make(segments, 0, 5*len(cs))
+		for _, c := range cs {
+			path = append(path,
+				opMoveTo(c.Add(f32.Pt(-r, -r))),
+				opLineTo(c.Add(f32.Pt(+r, -r))),
+				opLineTo(c.Add(f32.Pt(+r, +r))),
+				opLineTo(c.Add(f32.Pt(-r, +r))),
+				segment{op: segOpClose},
+			)
+		}
+	}
 
+	defer op.TransformOp{}.Push(p.ctx.Ops).Pop()
 	paint.FillShape(
 		p.ctx.Ops,
 		rgba(p.stk.cur().stroke.color),
-		path.stroke(p.ctx.Ops, p.stk.cur().stroke),
+		path.outline(p.ctx.Ops),
 	)
 }
 
@@ -161,13 +287,121 @@ func (p *Proc) Quad(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
 }
 
 // Rect draws a rectangle at (x,y) with width w and height h.
-func (p *Proc) Rect(x, y, w, h float64) {
-	p.Quad(x, y, x+w, y, x+w, y+h, x, y+h)
+//
+// The corners of the rectangle are rounded when radii are provided:
+// a single radius is applied to all corners, otherwise radii are, in
+// order, the radii of the top-left, top-right, bottom-right and
+// bottom-left corners. Missing radii take the value of the previous one.
+func (p *Proc) Rect(x, y, w, h float64, radii ...float64) {
+	x, y, w, h = p.stk.cur().rectMode.box(x, y, w, h)
+	if len(radii) == 0 {
+		p.Quad(x, y, x+w, y, x+w, y+h, x, y+h)
+		return
+	}
+	p.roundRect(x, y, w, h, radii)
 }
 
 // Square draws a square at (x,y) with size s.
-func (p *Proc) Square(x, y, s float64) {
-	p.Rect(x, y, s, s)
+//
+// The corners of the square are rounded when radii are provided,
+// as for Rect.
+func (p *Proc) Square(x, y, s float64, radii ...float64) {
+	p.Rect(x, y, s, s, radii...)
+}
+
+func (p *Proc) roundRect(x, y, w, h float64, radii []float64) {
+	if !p.doShape() {
+		return
+	}
+
+	if w < 0 {
+		x, w = x+w, -w
+	}
+	if h < 0 {
+		y, h = y+h, -h
+	}
+
+	// corner radii, clockwise from the top-left corner.
+	var (
+		rs   [4]float64
+		rmax = 0.5 * math.Min(w, h)
+	)
+	for i := range rs {
+		switch {
+		case i < len(radii):
+			rs[i] = math.Max(0, math.Min(radii[i], rmax))
+		default:
+			rs[i] = rs[i-1]
+		}
+	}
+
+	var (
+		// corners, edge directions and the direction pointing
+		// to the center of the rectangle.
+		corners = [4][2]float64{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}
+		edges   = [4][2]float64{{+1, 0}, {0, +1}, {-1, 0}, {0, -1}}
+		inward  = [4][2]float64{{+1, +1}, {-1, +1}, {-1, -1}, {+1, -1}}
+
+		// quarter turns are clockwise on screen, unless the
+		// canvas coordinates are mirrored.
+		turn = float32(0.5 * math.Pi)
+		path = make(segments, 0, 10)
+	)
+	if p.mirrored() {
+		turn = -turn
+	}
+
+	for i, c := range corners {
+		var (
+			r   = rs[i]
+			in  = inward[i]
+			out = edges[(i+3)%4]
+			beg = p.pt(c[0]-out[0]*r, c[1]-out[1]*r)
+		)
+		switch i {
+		case 0:
+			path = append(path, opMoveTo(beg))
+		default:
+			path = append(path, opLineTo(beg))
+		}
+		if r == 0 {
+			continue
+		}
+		f1, f2 := p.foci(c[0]+in[0]*r, c[1]+in[1]*r, r, r)
+		path = append(path, opArcTo(f1, f2, turn))
+	}
+	path = append(path, segment{op: segOpClose})
+
+	p.fillShape(path)
+	p.strokeShape(path)
+}
+
+// mirrored returns whether the conversion from user to system
+// coordinates reverses the orientation of shapes.
+func (p *Proc) mirrored() bool {
+	var (
+		sx = p.cfg.u2sX(1) - p.cfg.u2sX(0)
+		sy = p.cfg.u2sY(1) - p.cfg.u2sY(0)
+	)
+	return sx*sy < 0
+}
+
+// foci returns the foci, in system coordinates, of the axis-aligned
+// ellipse centered at (x,y), with the a and b semi-axes in user coordinates.
+func (p *Proc) foci(x, y, a, b float64) (f1, f2 f32.Point) {
+	var (
+		c  = p.pt(x, y)
+		sa = math.Abs(p.cfg.u2sX(x+a) - p.cfg.u2sX(x))
+		sb = math.Abs(p.cfg.u2sY(y+b) - p.cfg.u2sY(y))
+	)
+	switch {
+	case sa >= sb:
+		f := float32(math.Sqrt(sa*sa - sb*sb))
+		return c.Add(f32.Pt(+f, 0)), c.Add(f32.Pt(-f, 0))
+	default:
+		f := float32(math.Sqrt(sb*sb - sa*sa))
+		return c.Add(f32.Pt(0, +f)), c.Add(f32.Pt(0, -f))
+	}
 }
 
 // Triangle draws a triangle, connecting the 3 points (x1,y1), (x2,y2)
@@ -198,13 +432,7 @@ func (p *Proc) Bezier(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
 		}
 	)
 
-	defer op.TransformOp{}.Push(p.ctx.Ops).Pop()
-
-	paint.FillShape(
-		p.ctx.Ops,
-		rgba(p.stk.cur().stroke.color),
-		path.stroke(p.ctx.Ops, p.stk.cur().stroke),
-	)
+	p.strokeShape(path)
 }
 
 // Curve draws a curved line starting at (x2,y2) and ending at (x3,y3).
@@ -232,12 +460,9 @@ func (p *Proc) Curve(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
 		cr3 = p.pt(x3, y3)
 		cr4 = p.pt(x4, y4)
 
-		itau = 1 / (6 * (1 - tau))
-
-		beg = cr2
-		cp0 = cr2.Add(cr3.Sub(cr1).Mul(itau))
-		cp1 = cr3.Sub(cr4.Sub(cr2).Mul(itau))
-		end = cr3
+		beg      = cr2
+		cp0, cp1 = catmullRom(cr1, cr2, cr3, cr4, tau)
+		end      = cr3
 
 		path = segments{
 			opMoveTo(beg),
@@ -245,13 +470,73 @@ func (p *Proc) Curve(x1, y1, x2, y2, x3, y3, x4, y4 float64) {
 		}
 	)
 
-	defer op.TransformOp{}.Push(p.ctx.Ops).Pop()
+	p.strokeShape(path)
+}
 
-	paint.FillShape(
-		p.ctx.Ops,
-		rgba(p.stk.cur().stroke.color),
-		path.stroke(p.ctx.Ops, p.stk.cur().stroke),
-	)
+// catmullRom returns the control points of the cubic Bézier curve from cr2
+// to cr3, matching the Catmull-Rom spline through cr1, cr2, cr3 and cr4
+// with the tau tension.
+func catmullRom(cr1, cr2, cr3, cr4 f32.Point, tau float32) (cp0, cp1 f32.Point) {
+	itau := float32(catmullRomScale(float64(tau)))
+	cp0 = cr2.Add(cr3.Sub(cr1).Mul(itau))
+	cp1 = cr3.Sub(cr4.Sub(cr2).Mul(itau))
+	return cp0, cp1
+}
+
+// catmullRomScale returns the factor applied to the tangents of a
+// Catmull-Rom spline with the tau tension, to get the control points of
+// the matching cubic Bézier curves.
+func catmullRomScale(tau float64) float64 {
+	if tau == 1 {
+		return 0
+	}
+	return 1 / (6 * (1 - tau))
+}
+
+// BezierPoint evaluates the cubic Bézier curve with the a and d anchors
+// and the b and c control points, at t in [0,1].
+//
+// BezierPoint works on a single coordinate: calling it for the x and y
+// coordinates of a curve yields the position of a point on that curve.
+func BezierPoint(a, b, c, d, t float64) float64 {
+	u := 1 - t
+	return u*u*u*a + 3*u*u*t*b + 3*u*t*t*c + t*t*t*d
+}
+
+// BezierTangent evaluates the derivative of the cubic Bézier curve with
+// the a and d anchors and the b and c control points, at t in [0,1].
+//
+// Calling BezierTangent for the x and y coordinates of a curve yields the
+// direction of the tangent to that curve, e.g. with math.Atan2(ty, tx).
+func BezierTangent(a, b, c, d, t float64) float64 {
+	u := 1 - t
+	return 3*u*u*(b-a) + 6*u*t*(c-b) + 3*t*t*(d-c)
+}
+
+// CurvePoint evaluates the Catmull-Rom curve going from b to c, with the
+// a and d control points, at t in [0,1].
+// CurvePoint honors the tension set with CurveTightness.
+//
+// CurvePoint works on a single coordinate: calling it for the x and y
+// coordinates of a curve yields the position of a point on that curve.
+func (p *Proc) CurvePoint(a, b, c, d, t float64) float64 {
+	b, c1, c2, c := p.curveBezier(a, b, c, d)
+	return BezierPoint(b, c1, c2, c, t)
+}
+
+// CurveTangent evaluates the derivative of the Catmull-Rom curve going
+// from b to c, with the a and d control points, at t in [0,1].
+// CurveTangent honors the tension set with CurveTightness.
+func (p *Proc) CurveTangent(a, b, c, d, t float64) float64 {
+	b, c1, c2, c := p.curveBezier(a, b, c, d)
+	return BezierTangent(b, c1, c2, c, t)
+}
+
+// curveBezier returns the anchors and control points of the cubic Bézier
+// curve matching the Catmull-Rom curve with the current tension.
+func (p *Proc) curveBezier(a, b, c, d float64) (p0, p1, p2, p3 float64) {
+	s := catmullRomScale(float64(p.stk.cur().tau))
+	return b, b + (c-a)*s, c - (d-b)*s, c
 }
 
 // CurveTightness determines how the curve fits to the Curve vertex points.
@@ -263,7 +548,7 @@ func (p *Proc) CurveTightness(v float64) {
 }
 
 func (p *Proc) poly(ps ...f32.Point) {
-	if !p.doFill() && !p.doStroke() {
+	if !p.doShape() {
 		return
 	}
 
@@ -285,23 +570,42 @@ func (p *Proc) poly(ps ...f32.Point) {
 		path[len(path)-1] = segment{op: segOpClose}
 	}
 
-	if p.doFill() {
-		stack := op.TransformOp{}.Push(p.ctx.Ops)
-		paint.FillShape(
-			p.ctx.Ops,
-			rgba(p.stk.cur().fill),
-			path.outline(p.ctx.Ops),
-		)
-		stack.Pop()
+	p.fillShape(path)
+	p.strokeShape(path)
+}
+
+// doShape returns whether a closed shape should be processed, either
+// because it is painted or because it is part of a clipping mask.
+func (p *Proc) doShape() bool {
+	return p.doFill() || p.doStroke() || p.stk.mask != nil
+}
+
+// fillShape paints the area enclosed by path with the current fill color,
+// or adds it to the clipping mask being recorded.
+func (p *Proc) fillShape(path segments) {
+	if mask := p.stk.mask; mask != nil {
+		mask.add(path, p.stk.cur().aff)
+		return
+	}
+	if !p.doFill() {
+		return
 	}
 
-	if p.doStroke() {
-		stack := op.TransformOp{}.Push(p.ctx.Ops)
-		paint.FillShape(
-			p.ctx.Ops,
-			rgba(p.stk.cur().stroke.color),
-			path.stroke(p.ctx.Ops, p.stk.cur().stroke),
-		)
-		stack.Pop()
+	p.paintShape(p.stk.cur().fill, path.outline(p.ctx.Ops))
+}
+
+// strokeShape paints path with the current stroke style.
+// Strokes are not part of clipping masks.
+func (p *Proc) strokeShape(path segments) {
+	if p.stk.mask != nil || !p.doStroke() {
+		return
 	}
+
+	p.paintShape(p.stk.cur().stroke.color, path.stroke(p.ctx.Ops, p.stk.cur().stroke))
+}
+
+// paintShape paints the area of the shape clip operation with the c color.
+func (p *Proc) paintShape(c color.Color, shape clip.Op) {
+	defer op.TransformOp{}.Push(p.ctx.Ops).Pop()
+	paint.FillShape(p.ctx.Ops, rgba(c), shape)
 }
@@ -0,0 +1,692 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/x/stroke"
+	"golang.org/x/image/colornames"
+)
+
+// SVG is a vector graphics document, read with ReadSVG and drawn with
+// DrawSVG.
+//
+// Only the common subset of SVG is supported: the rect, circle, ellipse,
+// line, polyline, polygon and path elements, possibly nested in groups,
+// with their transforms and their fill, stroke and opacity attributes.
+// Other elements, such as text, images, gradients or filters, are ignored.
+type SVG struct {
+	// Width and Height are the intrinsic size of the document.
+	Width, Height float64
+
+	view   [4]float64 // viewBox of the document: min-x, min-y, width, height.
+	shapes []svgShape
+}
+
+// svgShape is a shape of an SVG document, with its path in the
+// coordinates of the viewBox.
+type svgShape struct {
+	path   *Path
+	fill   color.Color
+	stroke color.Color
+	width  float64
+	cap    stroke.StrokeCap
+	join   stroke.StrokeJoin
+}
+
+// svgStyle holds the presentation attributes of an SVG element,
+// as inherited from its ancestors.
+type svgStyle struct {
+	color  color.Color // value of currentColor.
+	fill   color.Color // nil when shapes are not filled.
+	stroke color.Color // nil when shapes are not stroked.
+
+	opacity       float64 // group opacity, applied to fill and stroke.
+	fillOpacity   float64
+	strokeOpacity float64
+
+	width float64
+	cap   stroke.StrokeCap
+	join  stroke.StrokeJoin
+	rule  FillRule
+
+	ctm f32.Affine2D // transformation from element to viewBox coordinates.
+}
+
+// ReadSVG reads the SVG document at the provided path.
+func (p *Proc) ReadSVG(fname string) (*SVG, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, fmt.Errorf("p5: could not open SVG document %q: %w", fname, err)
+	}
+	defer f.Close()
+
+	doc, err := decodeSVG(f)
+	if err != nil {
+		return nil, fmt.Errorf("p5: could not read SVG document %q: %w", fname, err)
+	}
+	return doc, nil
+}
+
+// DrawSVG draws the SVG document in the w×h rectangle at (x,y).
+//
+// As with DrawImage, (x,y) is the center of the rectangle with ModeCenter
+// or ModeRadius image modes, and its corner otherwise.
+// The document is drawn upright and its viewBox is stretched to fill the
+// rectangle. When w or h is not positive, the intrinsic size of the
+// document is used instead.
+//
+// Shapes are drawn with their own fill and stroke styles, under the
+// current transformation.
+func (p *Proc) DrawSVG(doc *SVG, x, y, w, h float64) {
+	if w <= 0 || h <= 0 {
+		w, h = doc.Width, doc.Height
+	}
+	switch p.stk.cur().imageMode {
+	case ModeCenter, ModeRadius:
+		x -= 0.5 * w
+		y -= 0.5 * h
+	}
+
+	var (
+		x0, x1 = p.cfg.u2sX(x), p.cfg.u2sX(x + w)
+		y0, y1 = p.cfg.u2sY(y), p.cfg.u2sY(y + h)
+		sx     = math.Abs(x1-x0) / doc.view[2]
+		sy     = math.Abs(y1-y0) / doc.view[3]
+	)
+
+	p.stk.push()
+	defer p.stk.pop()
+
+	p.stk.matrix(f32.NewAffine2D(
+		float32(sx), 0, float32(math.Min(x0, x1)-sx*doc.view[0]),
+		0, float32(sy), float32(math.Min(y0, y1)-sy*doc.view[1]),
+	))
+
+	ctx := p.stk.cur()
+	ctx.stroke.style.dashes = stroke.Dashes{}
+	for _, shape := range doc.shapes {
+		ctx.fill = shape.fill
+		ctx.stroke.color = shape.stroke
+		ctx.stroke.style.width = float32(shape.width)
+		ctx.stroke.style.cap = shape.cap
+		ctx.stroke.style.join = shape.join
+		p.DrawPath(shape.path)
+	}
+}
+
+// decodeSVG decodes the SVG document read from r.
+func decodeSVG(r io.Reader) (*SVG, error) {
+	var (
+		doc SVG
+		dec = xml.NewDecoder(r)
+		stk []svgStyle // styles of the enclosing groups.
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode XML: %w", err)
+		}
+
+		var elem xml.StartElement
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			elem = tok
+		case xml.EndElement:
+			stk = stk[:len(stk)-1]
+			continue
+		default:
+			continue
+		}
+
+		if stk == nil {
+			if elem.Name.Local != "svg" {
+				return nil, fmt.Errorf("invalid root element %q", elem.Name.Local)
+			}
+			doc.init(svgAttrs(elem))
+			stk = append(stk, svgStyle{
+				color:         color.NRGBA{A: 255},
+				fill:          color.NRGBA{A: 255},
+				opacity:       1,
+				fillOpacity:   1,
+				strokeOpacity: 1,
+				width:         1,
+				cap:           stroke.FlatCap,
+				join:          stroke.MiterJoin,
+			})
+		}
+
+		sty, visible, err := stk[len(stk)-1].inherit(elem.Attr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q element: %w", elem.Name.Local, err)
+		}
+
+		switch {
+		case !visible:
+			err = dec.Skip()
+		case elem.Name.Local == "svg", elem.Name.Local == "g", elem.Name.Local == "a":
+			stk = append(stk, sty)
+		default:
+			err = doc.add(elem, sty)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q element: %w", elem.Name.Local, err)
+			}
+			err = dec.Skip()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode XML: %w", err)
+		}
+	}
+
+	if stk == nil {
+		return nil, fmt.Errorf("missing svg element")
+	}
+	return &doc, nil
+}
+
+// init sets the size and the viewBox of the document, from the attributes
+// of its root element.
+func (doc *SVG) init(attrs map[string]string) {
+	var (
+		w, wok = svgLength(attrs["width"])
+		h, hok = svgLength(attrs["height"])
+		vb, ok = svgNumbers(attrs["viewBox"])
+	)
+	if !ok || len(vb) != 4 || vb[2] <= 0 || vb[3] <= 0 {
+		if !wok {
+			w = 300
+		}
+		if !hok {
+			h = 150
+		}
+		vb = []float64{0, 0, w, h}
+	}
+	switch {
+	case !wok && !hok:
+		w, h = vb[2], vb[3]
+	case !wok:
+		w = h * vb[2] / vb[3]
+	case !hok:
+		h = w * vb[3] / vb[2]
+	}
+	doc.Width = w
+	doc.Height = h
+	copy(doc.view[:], vb)
+}
+
+// add adds the shape described by the elem element to the document.
+// Unsupported elements are ignored.
+func (doc *SVG) add(elem xml.StartElement, sty svgStyle) error {
+	var (
+		attrs = svgAttrs(elem)
+		num   = func(name string) float64 {
+			v, _ := svgLength(attrs[name])
+			return v
+		}
+		path = svgPath()
+	)
+	path.FillRule(sty.rule)
+
+	switch elem.Name.Local {
+	case "rect":
+		var (
+			x, y     = num("x"), num("y")
+			w, h     = num("width"), num("height")
+			rx, rxok = svgLength(attrs["rx"])
+			ry, ryok = svgLength(attrs["ry"])
+		)
+		if w <= 0 || h <= 0 {
+			return nil
+		}
+		switch {
+		case !rxok:
+			rx = ry
+		case !ryok:
+			ry = rx
+		}
+		rx = math.Min(math.Max(rx, 0), 0.5*w)
+		ry = math.Min(math.Max(ry, 0), 0.5*h)
+		if rx == 0 || ry == 0 {
+			path.Vertex(x, y)
+			path.Vertex(x+w, y)
+			path.Vertex(x+w, y+h)
+			path.Vertex(x, y+h)
+			path.Close()
+			break
+		}
+		path.Vertex(x+rx, y)
+		path.Vertex(x+w-rx, y)
+		path.ArcTo(rx, ry, 0, false, true, x+w, y+ry)
+		path.Vertex(x+w, y+h-ry)
+		path.ArcTo(rx, ry, 0, false, true, x+w-rx, y+h)
+		path.Vertex(x+rx, y+h)
+		path.ArcTo(rx, ry, 0, false, true, x, y+h-ry)
+		path.Vertex(x, y+ry)
+		path.ArcTo(rx, ry, 0, false, true, x+rx, y)
+		path.Close()
+	case "circle", "ellipse":
+		var (
+			cx, cy = num("cx"), num("cy")
+			rx, ry = num("rx"), num("ry")
+		)
+		if elem.Name.Local == "circle" {
+			rx = num("r")
+			ry = rx
+		}
+		if rx <= 0 || ry <= 0 {
+			return nil
+		}
+		path.Arc(cx, cy, rx, ry, 0, 2*math.Pi)
+		path.Close()
+	case "line":
+		path.Vertex(num("x1"), num("y1"))
+		path.Vertex(num("x2"), num("y2"))
+	case "polyline", "polygon":
+		vs, ok := svgNumbers(attrs["points"])
+		if !ok || len(vs)%2 != 0 {
+			return fmt.Errorf("invalid points %q", attrs["points"])
+		}
+		for i := 0; i < len(vs); i += 2 {
+			path.Vertex(vs[i], vs[i+1])
+		}
+		if elem.Name.Local == "polygon" {
+			path.Close()
+		}
+	case "path":
+		err := parsePathData(path, attrs["d"])
+		if err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	path.End()
+
+	if sty.ctm != (f32.Affine2D{}) {
+		path = path.derive(path.shape().transform(sty.ctm))
+		path.FillRule(sty.rule)
+	}
+
+	var (
+		fill   = sty.paint(sty.fill, sty.fillOpacity)
+		stroke = sty.paint(sty.stroke, sty.strokeOpacity)
+	)
+	if fill == nil && stroke == nil {
+		return nil
+	}
+
+	// strokes are drawn in viewBox coordinates: scale their width by the
+	// average scaling factor of the transformation.
+	sx, hx, _, hy, sy, _ := sty.ctm.Elems()
+	doc.shapes = append(doc.shapes, svgShape{
+		path:   path,
+		fill:   fill,
+		stroke: stroke,
+		width:  sty.width * math.Sqrt(math.Abs(float64(sx*sy-hx*hy))),
+		cap:    sty.cap,
+		join:   sty.join,
+	})
+	return nil
+}
+
+// svgPath returns a new retained path, whose user coordinates are the
+// coordinates of the viewBox of an SVG document.
+func svgPath() *Path {
+	id := func(v float64) float64 { return v }
+	return &Path{
+		kind:     ShapePolygon,
+		u2sX:     id,
+		u2sY:     id,
+		s2uX:     id,
+		s2uY:     id,
+		retained: true,
+	}
+}
+
+// svgAttrs returns the attributes of the elem element, by name.
+func svgAttrs(elem xml.StartElement) map[string]string {
+	attrs := make(map[string]string, len(elem.Attr))
+	for _, attr := range elem.Attr {
+		if attr.Name.Space != "" {
+			continue
+		}
+		attrs[attr.Name.Local] = strings.TrimSpace(attr.Value)
+	}
+	return attrs
+}
+
+// inherit returns the style of an element with the attrs attributes,
+// nested in an element with the sty style, and whether it is displayed.
+//
+// Presentation attributes may be set directly or with the style attribute,
+// which takes precedence. Invalid values are ignored.
+func (sty svgStyle) inherit(attrs []xml.Attr) (svgStyle, bool, error) {
+	props := make(map[string]string)
+	for _, attr := range attrs {
+		if attr.Name.Space != "" || attr.Name.Local == "style" {
+			continue
+		}
+		props[attr.Name.Local] = strings.TrimSpace(attr.Value)
+	}
+	for _, attr := range attrs {
+		if attr.Name.Space != "" || attr.Name.Local != "style" {
+			continue
+		}
+		for _, decl := range strings.Split(attr.Value, ";") {
+			k, v, ok := strings.Cut(decl, ":")
+			if !ok {
+				continue
+			}
+			v = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "!important"))
+			props[strings.TrimSpace(k)] = v
+		}
+	}
+
+	if props["display"] == "none" {
+		return sty, false, nil
+	}
+	if v, ok := props["transform"]; ok {
+		m, err := svgTransform(v)
+		if err != nil {
+			return sty, false, err
+		}
+		sty.ctm = sty.ctm.Mul(m)
+	}
+
+	if c, ok := svgColor(props["color"], sty.color); ok && c != nil {
+		sty.color = c
+	}
+	if c, ok := svgColor(props["fill"], sty.color); ok {
+		sty.fill = c
+	}
+	if c, ok := svgColor(props["stroke"], sty.color); ok {
+		sty.stroke = c
+	}
+	if v, ok := svgOpacity(props["opacity"]); ok {
+		sty.opacity *= v
+	}
+	if v, ok := svgOpacity(props["fill-opacity"]); ok {
+		sty.fillOpacity = v
+	}
+	if v, ok := svgOpacity(props["stroke-opacity"]); ok {
+		sty.strokeOpacity = v
+	}
+	if v, ok := svgLength(props["stroke-width"]); ok && v >= 0 {
+		sty.width = v
+	}
+	switch props["fill-rule"] {
+	case "nonzero":
+		sty.rule = NonZero
+	case "evenodd":
+		sty.rule = EvenOdd
+	}
+	switch props["stroke-linecap"] {
+	case "butt":
+		sty.cap = stroke.FlatCap
+	case "round":
+		sty.cap = stroke.RoundCap
+	case "square":
+		sty.cap = stroke.SquareCap
+	}
+	switch props["stroke-linejoin"] {
+	case "miter", "miter-clip", "arcs":
+		sty.join = stroke.MiterJoin
+	case "round":
+		sty.join = stroke.RoundJoin
+	case "bevel":
+		sty.join = stroke.BevelJoin
+	}
+	return sty, true, nil
+}
+
+// paint returns the c color, made translucent by the alpha opacity and the
+// opacity of the style, or nil when it is fully transparent.
+func (sty svgStyle) paint(c color.Color, alpha float64) color.Color {
+	alpha *= sty.opacity
+	if c == nil || alpha <= 0 {
+		return nil
+	}
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	nc.A = uint8(math.Round(float64(nc.A) * alpha))
+	if nc.A == 0 {
+		return nil
+	}
+	return nc
+}
+
+// svgUnits holds the size of absolute SVG units, in pixels.
+var svgUnits = map[string]float64{
+	"px": 1,
+	"pt": 96.0 / 72,
+	"pc": 16,
+	"mm": 96 / 25.4,
+	"cm": 96 / 2.54,
+	"in": 96,
+}
+
+// svgLength parses an SVG length, such as "10", "1.5px" or "2mm",
+// in pixels. Relative units are not supported.
+func svgLength(v string) (float64, bool) {
+	v = strings.TrimSpace(v)
+	scale := 1.0
+	if n := len(v) - 2; n > 0 {
+		if s, ok := svgUnits[v[n:]]; ok {
+			v, scale = v[:n], s
+		}
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f * scale, true
+}
+
+// svgNumbers parses a list of numbers, separated by white space or commas.
+func svgNumbers(v string) ([]float64, bool) {
+	var (
+		vs []float64
+		sc = pathDataScanner{s: v}
+	)
+	for {
+		sc.skip()
+		if sc.eof() {
+			return vs, v != ""
+		}
+		f, err := sc.float()
+		if err != nil {
+			return nil, false
+		}
+		vs = append(vs, f)
+	}
+}
+
+// svgOpacity parses an opacity, such as "0.5" or "50%", clamped to [0,1].
+func svgOpacity(v string) (float64, bool) {
+	scale := 1.0
+	if strings.HasSuffix(v, "%") {
+		v, scale = v[:len(v)-1], 0.01
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0, false
+	}
+	return math.Min(math.Max(f*scale, 0), 1), true
+}
+
+// svgColor parses an SVG paint, such as "none", "red", "#f00", "#ff0000"
+// or "rgb(255, 0, 0)", and returns whether it is valid.
+// Paint servers, such as gradients, are not supported and fall back on
+// their fallback color, if any.
+// The none paint yields a nil color, currentColor yields cur.
+func svgColor(v string, cur color.Color) (color.Color, bool) {
+	v = strings.TrimSpace(v)
+	switch lv := strings.ToLower(v); {
+	case v == "":
+		return nil, false
+	case lv == "none", lv == "transparent":
+		return nil, true
+	case lv == "currentcolor":
+		return cur, true
+	case strings.HasPrefix(lv, "url("):
+		_, fallback, ok := strings.Cut(v, ")")
+		if !ok {
+			return nil, false
+		}
+		if strings.TrimSpace(fallback) == "" {
+			return nil, true
+		}
+		return svgColor(fallback, cur)
+	case strings.HasPrefix(v, "#"):
+		hex := v[1:]
+		if len(hex) == 3 || len(hex) == 4 {
+			var b strings.Builder
+			for i := range hex {
+				b.WriteByte(hex[i])
+				b.WriteByte(hex[i])
+			}
+			hex = b.String()
+		}
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+		u, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 8 {
+			return nil, false
+		}
+		return color.NRGBA{R: uint8(u >> 24), G: uint8(u >> 16), B: uint8(u >> 8), A: uint8(u)}, true
+	case strings.HasPrefix(lv, "rgb(") || strings.HasPrefix(lv, "rgba("):
+		_, args, _ := strings.Cut(lv, "(")
+		args, ok := strings.CutSuffix(args, ")")
+		if !ok {
+			return nil, false
+		}
+		args = strings.NewReplacer(",", " ", "/", " ").Replace(args)
+		fs := strings.Fields(args)
+		if len(fs) != 3 && len(fs) != 4 {
+			return nil, false
+		}
+		var c [4]uint8
+		c[3] = 255
+		for i, f := range fs {
+			var (
+				v   float64
+				err error
+			)
+			switch {
+			case strings.HasSuffix(f, "%"):
+				v, err = strconv.ParseFloat(f[:len(f)-1], 64)
+				v *= 2.55
+			case i == 3:
+				v, err = strconv.ParseFloat(f, 64)
+				v *= 255
+			default:
+				v, err = strconv.ParseFloat(f, 64)
+			}
+			if err != nil {
+				return nil, false
+			}
+			c[i] = uint8(math.Round(math.Min(math.Max(v, 0), 255)))
+		}
+		return color.NRGBA{R: c[0], G: c[1], B: c[2], A: c[3]}, true
+	default:
+		c, ok := colornames.Map[lv]
+		if !ok {
+			return nil, false
+		}
+		return color.NRGBA(c), true
+	}
+}
+
+// svgTransform parses an SVG transform list, such as
+// "translate(10 20) rotate(45)".
+func svgTransform(v string) (f32.Affine2D, error) {
+	var (
+		m  f32.Affine2D
+		sc = pathDataScanner{s: v}
+	)
+	for {
+		sc.skip()
+		if sc.eof() {
+			return m, nil
+		}
+
+		beg := sc.pos
+		for !sc.eof() && sc.s[sc.pos] != '(' {
+			sc.pos++
+		}
+		if sc.eof() {
+			return m, fmt.Errorf("invalid transform %q", v)
+		}
+		name := strings.TrimSpace(sc.s[beg:sc.pos])
+		sc.pos++
+
+		var args []float32
+		for {
+			sc.skip()
+			if sc.eof() {
+				return m, fmt.Errorf("invalid transform %q", v)
+			}
+			if sc.s[sc.pos] == ')' {
+				sc.pos++
+				break
+			}
+			f, err := sc.float()
+			if err != nil {
+				return m, fmt.Errorf("invalid %s transform: %w", name, err)
+			}
+			args = append(args, float32(f))
+		}
+
+		var (
+			t  f32.Affine2D
+			ok bool
+		)
+		switch n := len(args); name {
+		case "matrix":
+			if ok = n == 6; ok {
+				t = f32.NewAffine2D(args[0], args[2], args[4], args[1], args[3], args[5])
+			}
+		case "translate":
+			if ok = n == 1 || n == 2; ok {
+				args = append(args, 0)
+				t = t.Offset(f32.Pt(args[0], args[1]))
+			}
+		case "scale":
+			if ok = n == 1 || n == 2; ok {
+				args = append(args, args[0])
+				t = t.Scale(f32.Point{}, f32.Pt(args[0], args[1]))
+			}
+		case "rotate":
+			if ok = n == 1 || n == 3; ok {
+				args = append(args, 0, 0)
+				t = t.Rotate(f32.Pt(args[1], args[2]), args[0]*math.Pi/180)
+			}
+		case "skewX":
+			if ok = n == 1; ok {
+				t = t.Shear(f32.Point{}, args[0]*math.Pi/180, 0)
+			}
+		case "skewY":
+			if ok = n == 1; ok {
+				t = t.Shear(f32.Point{}, 0, args[0]*math.Pi/180)
+			}
+		}
+		if !ok {
+			return m, fmt.Errorf("invalid %s transform with %d arguments", name, len(args))
+		}
+		m = m.Mul(t)
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"gioui.org/f32"
+	"gioui.org/x/stroke"
+)
+
+func TestReadSVG(t *testing.T) {
+	proc := newProc(200, 200)
+	doc, err := proc.ReadSVG("testdata/shapes.svg")
+	if err != nil {
+		t.Fatalf("could not read SVG: %+v", err)
+	}
+	if doc.Width != 200 || doc.Height != 100 {
+		t.Fatalf("invalid size: got=%vx%v, want=200x100", doc.Width, doc.Height)
+	}
+	if got, want := doc.view, [4]float64{0, 0, 100, 50}; got != want {
+		t.Fatalf("invalid viewBox: got=%v, want=%v", got, want)
+	}
+	if got, want := len(doc.shapes), 5; got != want {
+		t.Fatalf("invalid number of shapes: got=%d, want=%d", got, want)
+	}
+
+	_, err = proc.ReadSVG("testdata/missing.svg")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	// the viewBox is stretched to the drawn rectangle: record the shapes
+	// into a clipping mask to check where they land.
+	for _, tc := range []struct {
+		name       string
+		mode       ShapeMode
+		x, y, w, h float64
+		want       [4]float64
+	}{
+		{"intrinsic", ModeCorner, 0, 0, 0, 0, [4]float64{10, 10, 190, 90}},
+		{"stretched", ModeCenter, 100, 100, 100, 80, [4]float64{55, 68, 145, 132}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			proc := newProc(200, 200)
+			proc.ImageMode(tc.mode)
+			proc.BeginClip()
+			proc.DrawSVG(doc, tc.x, tc.y, tc.w, tc.h)
+
+			got := [4]float64{math.Inf(+1), math.Inf(+1), math.Inf(-1), math.Inf(-1)}
+			for _, shape := range proc.stk.mask.shapes {
+				for _, ps := range shape.transform(f32.Affine2D{}).polylines(flatTol) {
+					for _, pt := range ps {
+						got[0] = math.Min(got[0], float64(pt.X))
+						got[1] = math.Min(got[1], float64(pt.Y))
+						got[2] = math.Max(got[2], float64(pt.X))
+						got[3] = math.Max(got[3], float64(pt.Y))
+					}
+				}
+			}
+			for i := range got {
+				if math.Abs(got[i]-tc.want[i]) > 0.5 {
+					t.Fatalf("invalid bounds: got=%v, want=%v", got, tc.want)
+				}
+			}
+		})
+	}
+
+	tp := newTestProc(t, 200, 200,
+		func(p *Proc) {
+			p.Background(color.Gray{Y: 220})
+		},
+		func(p *Proc) {
+			// the document is 200x100 with a 100x50 viewBox: draw it at
+			// half its intrinsic size, so it is not resampled.
+			p.DrawSVG(doc, 0, 0, 100, 50)
+
+			p.ImageMode(ModeCenter)
+			p.DrawSVG(doc, 100, 150, 100, 50)
+		},
+		"testdata/svg.png",
+		imgDelta,
+	)
+	tp.Run(t)
+}
+
+func TestDecodeSVG(t *testing.T) {
+	const src = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="10 10 100 50">
+	<defs><rect width="100" height="100"/></defs>
+	<g fill="red" stroke="blue" stroke-width="2" opacity="0.5">
+		<rect x="10" y="20" width="30" height="10" fill-opacity="0.5"/>
+		<g transform="translate(100, 0) scale(2)">
+			<line x1="0" y1="0" x2="10" y2="0" style="stroke: currentColor; color: lime; stroke-linecap: square"/>
+		</g>
+		<circle cx="50" cy="50" r="5" display="none"/>
+	</g>
+	<polygon points="0,0 10,0 10,10" fill="none" stroke="#0f08" stroke-linejoin="bevel"/>
+	<text x="0" y="0">ignored</text>
+</svg>`
+
+	doc, err := decodeSVG(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("could not decode SVG: %+v", err)
+	}
+	if doc.Width != 100 || doc.Height != 50 {
+		t.Fatalf("invalid size: got=%vx%v, want=100x50", doc.Width, doc.Height)
+	}
+	if got, want := len(doc.shapes), 3; got != want {
+		t.Fatalf("invalid number of shapes: got=%d, want=%d", got, want)
+	}
+
+	for i, want := range []struct {
+		fill, stroke color.Color
+		width        float64
+		cap          stroke.StrokeCap
+		join         stroke.StrokeJoin
+		bounds       [4]float64
+	}{
+		{
+			fill:   color.NRGBA{R: 255, A: 64},
+			stroke: color.NRGBA{B: 255, A: 128},
+			width:  2,
+			cap:    stroke.FlatCap,
+			join:   stroke.MiterJoin,
+			bounds: [4]float64{10, 20, 40, 30},
+		},
+		{
+			fill:   color.NRGBA{R: 255, A: 128},
+			stroke: color.NRGBA{G: 255, A: 128},
+			width:  4,
+			cap:    stroke.SquareCap,
+			join:   stroke.MiterJoin,
+			bounds: [4]float64{100, 0, 120, 0},
+		},
+		{
+			stroke: color.NRGBA{G: 255, A: 136},
+			width:  1,
+			cap:    stroke.FlatCap,
+			join:   stroke.BevelJoin,
+			bounds: [4]float64{0, 0, 10, 10},
+		},
+	} {
+		shape := doc.shapes[i]
+		if shape.fill != want.fill {
+			t.Errorf("shape[%d]: invalid fill: got=%v, want=%v", i, shape.fill, want.fill)
+		}
+		if shape.stroke != want.stroke {
+			t.Errorf("shape[%d]: invalid stroke: got=%v, want=%v", i, shape.stroke, want.stroke)
+		}
+		if math.Abs(shape.width-want.width) > 1e-6 {
+			t.Errorf("shape[%d]: invalid width: got=%v, want=%v", i, shape.width, want.width)
+		}
+		if shape.cap != want.cap || shape.join != want.join {
+			t.Errorf("shape[%d]: invalid cap and join: got=(%v,%v), want=(%v,%v)",
+				i, shape.cap, shape.join, want.cap, want.join,
+			)
+		}
+		bnd := shape.path.Bounds()
+		got := [4]float64{bnd.Min.X, bnd.Min.Y, bnd.Max.X, bnd.Max.Y}
+		for j := range got {
+			if math.Abs(got[j]-want.bounds[j]) > 1e-4 {
+				t.Errorf("shape[%d]: invalid bounds: got=%v, want=%v", i, got, want.bounds)
+				break
+			}
+		}
+	}
+}
+
+func TestDecodeSVGShapes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		elem string
+		area float64
+	}{
+		{
+			name: "rect",
+			elem: `<rect x="10" y="10" width="20" height="10"/>`,
+			area: 200,
+		},
+		{
+			name: "rounded-rect",
+			elem: `<rect width="20" height="10" rx="5"/>`,
+			area: 200 - (100 - 25*math.Pi),
+		},
+		{
+			name: "circle",
+			elem: `<circle cx="20" cy="20" r="10"/>`,
+			area: 100 * math.Pi,
+		},
+		{
+			name: "rotated-ellipse",
+			elem: `<ellipse rx="20" ry="10" transform="rotate(30 10 10) skewX(0)"/>`,
+			area: 200 * math.Pi,
+		},
+		{
+			name: "matrix",
+			elem: `<rect width="10" height="10" transform="matrix(2 0 0 3 5 5)"/>`,
+			area: 600,
+		},
+		{
+			name: "path",
+			elem: `<path d="M0 0 H 10 V 10 H 0 Z M 2 2 H 8 V 8 H 2 Z" fill-rule="evenodd"/>`,
+			area: 64,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := decodeSVG(strings.NewReader(`<svg>` + tc.elem + `</svg>`))
+			if err != nil {
+				t.Fatalf("could not decode SVG: %+v", err)
+			}
+			if len(doc.shapes) != 1 {
+				t.Fatalf("invalid number of shapes: %d", len(doc.shapes))
+			}
+			if doc.Width != 300 || doc.Height != 150 {
+				t.Fatalf("invalid default size: %vx%v", doc.Width, doc.Height)
+			}
+			got := math.Abs(float64(doc.shapes[0].path.fill().transform(f32.Affine2D{}).area()))
+			if math.Abs(got-tc.area) > 1 {
+				t.Fatalf("invalid area: got=%v, want=%v", got, tc.area)
+			}
+		})
+	}
+}
+
+func TestDecodeSVGErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "root",
+			src:  `<html></html>`,
+			err:  `invalid root element "html"`,
+		},
+		{
+			name: "empty",
+			src:  ``,
+			err:  `missing svg element`,
+		},
+		{
+			name: "xml",
+			src:  `<svg><rect></svg>`,
+			err:  `could not decode XML`,
+		},
+		{
+			name: "path",
+			src:  `<svg><path d="L 10 10"/></svg>`,
+			err:  `path data must start with a move-to command`,
+		},
+		{
+			name: "points",
+			src:  `<svg><polygon points="0,0 10"/></svg>`,
+			err:  `invalid points "0,0 10"`,
+		},
+		{
+			name: "transform",
+			src:  `<svg><g transform="rotate(1 2)"/></svg>`,
+			err:  `invalid rotate transform with 2 arguments`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decodeSVG(strings.NewReader(tc.src))
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("invalid error: got=%q, want=%q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestSVGColor(t *testing.T) {
+	cur := color.NRGBA{R: 1, G: 2, B: 3, A: 255}
+	for _, tc := range []struct {
+		v    string
+		want color.Color
+		ok   bool
+	}{
+		{v: "none", ok: true},
+		{v: "currentColor", want: cur, ok: true},
+		{v: "red", want: color.NRGBA{R: 255, A: 255}, ok: true},
+		{v: "#0f0", want: color.NRGBA{G: 255, A: 255}, ok: true},
+		{v: "#0000ff80", want: color.NRGBA{B: 255, A: 128}, ok: true},
+		{v: "rgb(10, 20, 30)", want: color.NRGBA{R: 10, G: 20, B: 30, A: 255}, ok: true},
+		{v: "rgba(100%, 0%, 0%, 0.5)", want: color.NRGBA{R: 255, A: 128}, ok: true},
+		{v: "url(#grad) blue", want: color.NRGBA{B: 255, A: 255}, ok: true},
+		{v: "url(#grad)", ok: true},
+		{v: ""},
+		{v: "inherit"},
+		{v: "#12345"},
+		{v: "rgb(1, 2)"},
+	} {
+		t.Run(tc.v, func(t *testing.T) {
+			got, ok := svgColor(tc.v, cur)
+			if ok != tc.ok || got != tc.want {
+				t.Fatalf("invalid color: got=(%v, %v), want=(%v, %v)", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
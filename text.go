@@ -0,0 +1,605 @@
+// Copyright ©2026 The go-p5 Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package p5
+
+import (
+	"math"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/text"
+	"gioui.org/unit"
+	gotext "github.com/go-text/typesetting/font"
+	gotextot "github.com/go-text/typesetting/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// TextAlignment describes how text is positioned relative to the point
+// it is drawn at.
+type TextAlignment uint8
+
+const (
+	// AlignLeft aligns the left edge of the text on the point.
+	AlignLeft TextAlignment = iota
+
+	// AlignCenter aligns the center of the text on the point,
+	// horizontally or vertically.
+	AlignCenter
+
+	// AlignRight aligns the right edge of the text on the point.
+	AlignRight
+
+	// AlignTop aligns the top of the text on the point.
+	AlignTop
+
+	// AlignBaseline aligns the baseline of the first line of text on
+	// the point.
+	AlignBaseline
+
+	// AlignBottom aligns the bottom of the text on the point.
+	AlignBottom
+)
+
+// FontStyle describes the weight and slant of the text font.
+type FontStyle uint8
+
+const (
+	// StyleNormal selects a regular, upright font.
+	StyleNormal FontStyle = iota
+
+	// StyleBold selects a bold, upright font.
+	StyleBold
+
+	// StyleItalic selects a regular, italic font.
+	StyleItalic
+
+	// StyleBoldItalic selects a bold, italic font.
+	StyleBoldItalic
+)
+
+// TextWidth returns the width of txt, in user coordinates, when drawn
+// with the current text style.
+// The width of multi-line text is the width of its widest line.
+func (p *Proc) TextWidth(txt string) float64 {
+	w := p.layoutText(txt).width
+	return math.Abs(p.cfg.s2uX(w) - p.cfg.s2uX(0))
+}
+
+// TextAscent returns the ascent of the current font at the current text
+// size, in user coordinates: the distance from the baseline to the top
+// of a line of text.
+func (p *Proc) TextAscent() float64 {
+	lay := p.layoutText("")
+	return math.Abs(p.cfg.s2uY(lay.baseline) - p.cfg.s2uY(lay.top))
+}
+
+// TextDescent returns the descent of the current font at the current text
+// size, in user coordinates: the distance from the baseline to the bottom
+// of a line of text.
+func (p *Proc) TextDescent() float64 {
+	lay := p.layoutText("")
+	return math.Abs(p.cfg.s2uY(lay.bottom) - p.cfg.s2uY(lay.baseline))
+}
+
+// TextBounds returns the bounding box, in user coordinates, of txt when
+// drawn at (x,y) with Text and the current text style and alignment.
+// The current transformation is not taken into account.
+func (p *Proc) TextBounds(txt string, x, y float64) r2.Box {
+	var (
+		lay    = p.layoutText(txt)
+		dx, dy = lay.anchor(p.stk.cur().text.halign, p.stk.cur().text.valign)
+
+		x0 = p.cfg.s2uX(p.cfg.u2sX(x) - dx)
+		x1 = p.cfg.s2uX(p.cfg.u2sX(x) - dx + lay.width)
+		y0 = p.cfg.s2uY(p.cfg.u2sY(y) - dy + lay.top)
+		y1 = p.cfg.s2uY(p.cfg.u2sY(y) - dy + lay.bottom)
+	)
+	return r2.Box{
+		Min: r2.Vec{X: math.Min(x0, x1), Y: math.Min(y0, y1)},
+		Max: r2.Vec{X: math.Max(x0, x1), Y: math.Max(y0, y1)},
+	}
+}
+
+// TextBox draws txt within the box described by the (x,y,w,h) parameters,
+// interpreted according to the current rectangle mode.
+//
+// Lines are broken at explicit line breaks and wrapped at word boundaries
+// to fit the width of the box. Lines overflowing its height are dropped
+// and the last drawn line ends with an ellipsis.
+// Text is positioned in the box according to the current text alignment,
+// where AlignBaseline behaves as AlignTop.
+func (p *Proc) TextBox(txt string, x, y, w, h float64) {
+	if p.stk.mask != nil {
+		return
+	}
+
+	x, y, w, h = p.stk.cur().rectMode.box(x, y, w, h)
+	var (
+		x0, x1 = p.cfg.u2sX(x), p.cfg.u2sX(x + w)
+		y0, y1 = p.cfg.u2sY(y), p.cfg.u2sY(y + h)
+		width  = int(math.Abs(x1 - x0))
+		height = math.Abs(y1 - y0)
+	)
+	if width <= 0 {
+		return
+	}
+
+	var (
+		params = p.textParams(width, 0)
+		lay    = p.shapeText(txt, params)
+		n      = len(lay.lines)
+	)
+	for n > 0 && lay.lines[n-1]-lay.top > height {
+		n--
+	}
+	if n == 0 {
+		return
+	}
+	if n < len(lay.lines) {
+		params = p.textParams(width, n)
+		lay = p.shapeText(txt, params)
+	}
+
+	var (
+		top = math.Min(y0, y1) - lay.top
+		gap = height - (lay.bottom - lay.top)
+	)
+	switch p.stk.cur().text.valign {
+	case AlignCenter:
+		top += 0.5 * gap
+	case AlignBottom:
+		top += gap
+	}
+	p.drawText(txt, params, math.Min(x0, x1), top)
+}
+
+// TextToPath returns the outlines of the glyphs of txt, as drawn at (x,y)
+// by Text with the current text style and alignment.
+//
+// The returned path is retained, as if created with NewPath.
+// Glyphs without outlines, such as bitmap emojis, and glyphs from system
+// fonts outside of the fonts collection are left out.
+func (p *Proc) TextToPath(txt string, x, y float64) *Path {
+	var (
+		params, ox, oy = p.placeText(txt, x, y)
+
+		shaper = p.cfg.th.Shaper
+		faces  = p.fontFaces()
+		segs   segments
+	)
+	shaper.LayoutString(params, txt)
+	for g, ok := shaper.NextGlyph(); ok; g, ok = shaper.NextGlyph() {
+		idx, ppem, gid := splitGlyphID(g.ID)
+		if idx >= len(faces) || ppem == 0 {
+			continue
+		}
+		outline, ok := faces[idx].GlyphData(gid).(gotext.GlyphOutline)
+		if !ok {
+			continue
+		}
+
+		var (
+			scale = fix(ppem) / float64(faces[idx].Upem())
+			gx    = ox + fix(g.X-g.Offset.X)
+			gy    = oy + float64(g.Y) - fix(g.Offset.Y)
+			pt    = func(v gotextot.SegmentPoint) f32.Point {
+				return f32.Pt(
+					float32(gx+scale*float64(v.X)),
+					float32(gy-scale*float64(v.Y)),
+				)
+			}
+		)
+		for i, seg := range outline.Segments {
+			switch seg.Op {
+			case gotextot.SegmentOpMoveTo:
+				if i > 0 {
+					segs = append(segs, segment{op: segOpClose})
+				}
+				segs = append(segs, opMoveTo(pt(seg.Args[0])))
+			case gotextot.SegmentOpLineTo:
+				segs = append(segs, opLineTo(pt(seg.Args[0])))
+			case gotextot.SegmentOpQuadTo:
+				segs = append(segs, opQuadTo(pt(seg.Args[0]), pt(seg.Args[1])))
+			case gotextot.SegmentOpCubeTo:
+				segs = append(segs, opCubeTo(pt(seg.Args[0]), pt(seg.Args[1]), pt(seg.Args[2])))
+			}
+		}
+		if len(outline.Segments) > 0 {
+			segs = append(segs, segment{op: segOpClose})
+		}
+	}
+	return p.NewPath().derive(segs)
+}
+
+// TextToPoints returns points evenly spaced along the outlines of the
+// glyphs of txt, as drawn at (x,y) by Text, in user coordinates.
+//
+// The sampleFactor sets the number of points per unit of outline length.
+func (p *Proc) TextToPoints(txt string, x, y, sampleFactor float64) (xs, ys []float64) {
+	path := p.TextToPath(txt, x, y)
+	return path.Sample(int(math.Ceil(path.Length() * sampleFactor)))
+}
+
+// TextOnPath draws txt along the path, with the baseline of each glyph
+// following the path and each glyph rotated to the direction of the path
+// at its middle.
+//
+// The offset is the distance along the path, in user coordinates, where
+// the text is anchored according to the current horizontal alignment.
+// The current vertical alignment places the text across the path.
+// Line breaks are drawn as spaces, and glyphs whose middle lies beyond
+// the ends of the path are left out.
+func (p *Proc) TextOnPath(txt string, path *Path, offset float64) {
+	var (
+		sty   = p.stk.cur().text
+		width = p.stk.cur().stroke.style.width
+	)
+	if p.stk.mask != nil || path == nil || (sty.color == nil && (sty.stroke == nil || width <= 0)) {
+		return
+	}
+	trk := newTrack(path)
+	if len(trk) == 0 {
+		return
+	}
+
+	params := p.textParams(math.MaxInt32, 0)
+	params.Alignment = text.Start
+
+	var (
+		glyphs = p.glyphs(lineBreaks.Replace(txt), params)
+		minX   = fixed.Int26_6(math.MaxInt32)
+	)
+	for _, g := range glyphs {
+		minX = min(minX, g.X)
+	}
+
+	var (
+		lay    = measureGlyphs(glyphs)
+		dx, dy = lay.anchor(sty.halign, sty.valign)
+		start  = trk.at(offset) - dx
+		shift  = float32(lay.baseline - dy)
+	)
+	for _, g := range glyphs {
+		var (
+			adv    = fix(g.Advance)
+			pt, ok = trk.point(start + fix(g.X-minX) + 0.5*adv)
+		)
+		if !ok {
+			continue
+		}
+		m := f32.Affine2D{}.
+			Offset(f32.Pt(float32(-0.5*adv), shift)).
+			Rotate(f32.Point{}, pt.angle).
+			Offset(pt.pos)
+		stk := op.Affine(m).Push(p.ctx.Ops)
+		p.paintGlyphs([]text.Glyph{g})
+		stk.Pop()
+	}
+}
+
+// lineBreaks replaces line breaks with spaces.
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// track is the outline of a path, as line segments in system coordinates,
+// along which text is laid out.
+type track []trackEdge
+
+// trackEdge is a line segment of a track.
+type trackEdge struct {
+	a, b  f32.Point // ends, in system coordinates.
+	s, ds float64   // arc length at a and length, in system coordinates.
+	u, du float64   // arc length at a and length, in user coordinates.
+}
+
+// trackPoint is a point along a track, in system coordinates, with the
+// direction of the track at that point.
+type trackPoint struct {
+	pos   f32.Point
+	angle float32
+}
+
+// newTrack returns the track of the path.
+// The gaps between sub-paths do not count towards arc lengths.
+func newTrack(path *Path) track {
+	var (
+		trk  track
+		s, u float64
+	)
+	for _, ps := range path.user().polylines(path.userTol()) {
+		for i := 1; i < len(ps); i++ {
+			var (
+				a = f32.Pt(float32(path.u2sX(float64(ps[i-1].X))), float32(path.u2sY(float64(ps[i-1].Y))))
+				b = f32.Pt(float32(path.u2sX(float64(ps[i].X))), float32(path.u2sY(float64(ps[i].Y))))
+				e = trackEdge{a: a, b: b, s: s, ds: dist(a, b), u: u, du: dist(ps[i-1], ps[i])}
+			)
+			if e.ds == 0 {
+				continue
+			}
+			trk = append(trk, e)
+			s += e.ds
+			u += e.du
+		}
+	}
+	return trk
+}
+
+// at returns the arc length, in system coordinates, of the point at the
+// u arc length in user coordinates.
+// Arc lengths beyond the ends of the track are extrapolated.
+func (trk track) at(u float64) float64 {
+	e := trk[len(trk)-1]
+	for _, v := range trk {
+		if u < v.u+v.du {
+			e = v
+			break
+		}
+	}
+	if e.du == 0 {
+		return e.s
+	}
+	return e.s + (u-e.u)*e.ds/e.du
+}
+
+// point returns the point at the s arc length, in system coordinates, and
+// whether it lies on the track.
+func (trk track) point(s float64) (trackPoint, bool) {
+	last := trk[len(trk)-1]
+	if s < 0 || s > last.s+last.ds {
+		return trackPoint{}, false
+	}
+	e := last
+	for _, v := range trk {
+		if s <= v.s+v.ds {
+			e = v
+			break
+		}
+	}
+	var (
+		d = e.b.Sub(e.a)
+		t = float32((s - e.s) / e.ds)
+	)
+	return trackPoint{
+		pos:   e.a.Add(d.Mul(t)),
+		angle: float32(math.Atan2(float64(d.Y), float64(d.X))),
+	}, true
+}
+
+// textLayout holds the extent of a shaped text, in pixels, relative to
+// the upper-left corner of its layout.
+type textLayout struct {
+	width    float64   // width of the widest line.
+	top      float64   // top of the first line.
+	baseline float64   // baseline of the first line.
+	bottom   float64   // bottom of the last line.
+	lines    []float64 // bottom of each line.
+}
+
+// textParams returns the parameters to shape text with the current text
+// style, wrapped at maxWidth pixels and, unless zero, truncated after
+// maxLines lines.
+func (p *Proc) textParams(maxWidth, maxLines int) text.Parameters {
+	sty := p.stk.cur().text
+	params := text.Parameters{
+		Font:       sty.font,
+		PxPerEm:    fixed.I(p.ctx.Sp(unit.Sp(sty.size))),
+		MaxWidth:   maxWidth,
+		MaxLines:   maxLines,
+		WrapPolicy: text.WrapWords,
+		Locale:     p.ctx.Locale,
+	}
+	switch sty.halign {
+	case AlignCenter:
+		params.Alignment = text.Middle
+	case AlignRight:
+		params.Alignment = text.End
+	}
+	if maxLines > 0 {
+		params.Truncator = "…"
+	}
+	if sty.leading > 0 {
+		params.LineHeight = fixed.I(p.ctx.Sp(unit.Sp(sty.leading)))
+		params.LineHeightScale = 1
+	}
+	return params
+}
+
+// placeText returns the parameters to shape txt and the position, in
+// system coordinates, of the upper-left corner of its layout when drawn
+// at (x,y), according to the current text alignment.
+func (p *Proc) placeText(txt string, x, y float64) (params text.Parameters, ox, oy float64) {
+	var (
+		sty = p.stk.cur().text
+		lay = p.layoutText(txt)
+	)
+	// lines are aligned within the whole pixels of the layout.
+	lay.width = math.Ceil(lay.width)
+	dx, dy := lay.anchor(sty.halign, sty.valign)
+	return p.textParams(int(lay.width), 0), p.cfg.u2sX(x) - dx, p.cfg.u2sY(y) - dy
+}
+
+// layoutText shapes txt with the current text style, only breaking lines
+// at explicit line breaks.
+func (p *Proc) layoutText(txt string) textLayout {
+	return p.shapeText(txt, p.textParams(math.MaxInt32, 0))
+}
+
+// shapeText shapes txt with the params parameters.
+func (p *Proc) shapeText(txt string, params text.Parameters) textLayout {
+	return measureGlyphs(p.glyphs(txt, params))
+}
+
+// glyphs shapes txt with the params parameters and returns its glyphs.
+func (p *Proc) glyphs(txt string, params text.Parameters) []text.Glyph {
+	var (
+		shaper = p.cfg.th.Shaper
+		glyphs []text.Glyph
+	)
+	shaper.LayoutString(params, txt)
+	for g, ok := shaper.NextGlyph(); ok; g, ok = shaper.NextGlyph() {
+		glyphs = append(glyphs, g)
+	}
+	return glyphs
+}
+
+// measureGlyphs returns the layout of the shaped glyphs.
+func measureGlyphs(glyphs []text.Glyph) textLayout {
+	var (
+		lay  textLayout
+		line = int32(math.MinInt32) // baseline of the current line.
+
+		minX, maxX fixed.Int26_6
+	)
+	for _, g := range glyphs {
+		var (
+			y      = fixed.I(int(g.Y))
+			top    = fix(y - g.Ascent)
+			bottom = fix(y + g.Descent)
+		)
+		if lay.lines == nil {
+			minX, maxX = g.X, g.X+g.Advance
+			lay.top, lay.baseline, lay.bottom = top, fix(y), bottom
+		}
+		if g.Y != line {
+			line = g.Y
+			lay.lines = append(lay.lines, bottom)
+		}
+		minX = min(minX, g.X)
+		maxX = max(maxX, g.X+g.Advance)
+		lay.top = math.Min(lay.top, top)
+		lay.bottom = math.Max(lay.bottom, bottom)
+		lay.lines[len(lay.lines)-1] = math.Max(lay.lines[len(lay.lines)-1], bottom)
+	}
+	lay.width = fix(maxX - minX)
+	return lay
+}
+
+// drawText draws txt, shaped with the params parameters, with the
+// upper-left corner of its layout at (x,y) in system coordinates.
+// Glyphs are filled with the text color and outlined with the text stroke.
+func (p *Proc) drawText(txt string, params text.Parameters, x, y float64) {
+	var (
+		sty    = p.stk.cur().text
+		width  = p.stk.cur().stroke.style.width
+		shaper = p.cfg.th.Shaper
+		line   []text.Glyph
+	)
+	if sty.color == nil && (sty.stroke == nil || width <= 0) {
+		return
+	}
+
+	defer op.TransformOp{}.Push(p.ctx.Ops).Pop()
+	op.Affine(f32.Affine2D{}.Offset(f32.Point{
+		X: float32(x),
+		Y: float32(y),
+	})).Add(p.ctx.Ops)
+
+	draw := func() {
+		if len(line) == 0 {
+			return
+		}
+		defer op.Affine(f32.Affine2D{}.Offset(f32.Point{
+			X: float32(fix(line[0].X)),
+			Y: float32(line[0].Y),
+		})).Push(p.ctx.Ops).Pop()
+
+		p.paintGlyphs(line)
+		line = line[:0]
+	}
+
+	shaper.LayoutString(params, txt)
+	for g, ok := shaper.NextGlyph(); ok; g, ok = shaper.NextGlyph() {
+		line = append(line, g)
+		if g.Flags&text.FlagLineBreak != 0 {
+			draw()
+		}
+	}
+	draw()
+}
+
+// paintGlyphs paints the glyphs, positioned relative to the dot of the
+// first one, filled with the text color and outlined with the text stroke.
+func (p *Proc) paintGlyphs(glyphs []text.Glyph) {
+	var (
+		sty    = p.stk.cur().text
+		width  = p.stk.cur().stroke.style.width
+		shaper = p.cfg.th.Shaper
+		path   = shaper.Shape(glyphs)
+	)
+	if sty.color != nil {
+		p.paintShape(sty.color, clip.Outline{Path: path}.Op())
+		if call := shaper.Bitmaps(glyphs); call != (op.CallOp{}) {
+			call.Add(p.ctx.Ops)
+		}
+	}
+	if sty.stroke != nil && width > 0 {
+		p.paintShape(sty.stroke, clip.Stroke{Path: path, Width: width}.Op())
+	}
+}
+
+// anchor returns the offset, in pixels, from the upper-left corner of the
+// text layout to the point it is drawn at, according to the current text
+// alignment.
+func (lay textLayout) anchor(h, v TextAlignment) (dx, dy float64) {
+	switch h {
+	case AlignCenter:
+		dx = 0.5 * lay.width
+	case AlignRight:
+		dx = lay.width
+	}
+	switch v {
+	case AlignTop:
+		dy = lay.top
+	case AlignCenter:
+		dy = 0.5 * (lay.top + lay.bottom)
+	case AlignBottom:
+		dy = lay.bottom
+	default:
+		dy = lay.baseline
+	}
+	return dx, dy
+}
+
+// fontFaces returns the faces of the fonts collection, indexed as in the
+// glyph identifiers of the text shaper: faces are registered in the order
+// of the collection, skipping duplicates.
+func (p *Proc) fontFaces() []*gotext.Face {
+	var (
+		faces []*gotext.Face
+		seen  = make(map[*gotext.Font]bool)
+	)
+	for _, ff := range p.cfg.fonts {
+		face := ff.Face.Face()
+		if seen[face.Font] {
+			continue
+		}
+		seen[face.Font] = true
+		faces = append(faces, face)
+	}
+	return faces
+}
+
+// splitGlyphID returns the index of the face, the size in pixels per em
+// and the identifier within the face of the glyph, packed into a glyph
+// identifier of the text shaper.
+// Synthetic glyphs, such as line breaks, have a zero size.
+func splitGlyphID(id text.GlyphID) (face int, ppem fixed.Int26_6, gid gotext.GID) {
+	const (
+		faceBits = 16
+		sizeBits = 16
+		gidBits  = 64 - faceBits - sizeBits
+	)
+	face = int(uint64(id) >> (gidBits + sizeBits))
+	ppem = fixed.Int26_6(uint64(id) >> gidBits & (1<<sizeBits - 1))
+	gid = gotext.GID(id & (1<<gidBits - 1))
+	return face, ppem, gid
+}
+
+// fix converts a fixed-point value to a float64.
+func fix(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}